@@ -5,6 +5,8 @@ import (
 	"flag"
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -13,7 +15,16 @@ import (
 // ProvideStruct updates cfg using values from given providers. Given cfg
 // must be a ref to struct with all exported fields having Value type and
 // struct tag with tags for given providers. Current values in cfg, if
-// any, will be used as defaults.
+// any, will be used as defaults (also see FromDefault).
+//
+// Exported fields may also be a nested struct, embedded struct or a pointer
+// to struct (nil pointer will be set to a new zero struct) which contains
+// Value fields or has tag like "envPrefix" - their fields will be handled
+// recursively, with name like "DB.Host" (or just "Host" for fields of
+// embedded struct). Value of tag "key" of a nested field
+// will be prefixed by value of tag "keyPrefix" of parent struct field,
+// e.g. field Host `env:"HOST"` in field DB `envPrefix:"DB_"` will have
+// tag `env:"DB_HOST"`.
 //
 // Providers will be called for each exported field in cfg, in order, with
 // next provider will be called only if previous providers won't provide a
@@ -23,23 +34,104 @@ import (
 // providers will be applied - this way usage message on -h flag will be
 // able to show values set by other providers as flag defaults.
 //
-// Returns error if any provider will try to set invalid value.
+// Source of each provided value is recorded, see SourceOf.
+//
+// Returns error if any provider will try to set invalid value. All such
+// errors (one per failed field) are returned joined using [errors.Join],
+// use FieldErrors to get them.
 func ProvideStruct(cfg any, providers ...Provider) error {
-	var lastErr error
-	forStruct(cfg, func(value Value, name string, tags Tags) {
+	var errs []error
+	forStructNew(cfg, func(value Value, name string, tags Tags) {
 		for _, provider := range providers {
 			ok, err := provider.Provide(value, name, tags)
 			if err != nil {
-				lastErr = fmt.Errorf("%s: %w", field(name, tags), err)
+				fieldErr := &FieldError{Field: name, Flag: flagName(value), Tags: tags, Err: err}
+				if env, ok := provider.(envNamer); ok {
+					fieldErr.Env = env.envName(tags)
+				}
+				if rawErr := new(rawError); errors.As(err, &rawErr) {
+					fieldErr.Raw = rawErr.raw
+				}
+				errs = append(errs, fieldErr)
 				break
 			}
 			if ok {
 				_ = value.Get() // Mark previous value as completed (in case it's a Slice).
+				if !hasSource(value) {
+					setSource(value, fmt.Sprintf("%T", provider), "", value.String())
+				}
 				break
 			}
 		}
 	})
-	return lastErr
+	return errors.Join(errs...)
+}
+
+// FieldError describes an error related to some cfg field.
+type FieldError struct {
+	Field string // Field name.
+	Env   string // Env name (like "$EXAMPLE_PORT") if error comes from FromEnv or FromDotenv.
+	Flag  string // Flag name (like "-port") if field was added to FlagSet.
+	Tags  Tags   // Field tags.
+	Raw   string // Raw input which failed Set (masked for secrets) if error comes from Provider.
+	Err   error  // Provider error (usually includes source and value) or validation error.
+}
+
+// Error implements error interface.
+func (e *FieldError) Error() string {
+	name, sources := e.Field, []any{e.Flag}
+	if name == "" {
+		name, sources = e.Flag, nil
+	}
+	if e.Tags != nil {
+		sources = append(sources, e.Tags)
+	}
+	if name == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", field(name, sources...), e.Err)
+}
+
+// Unwrap returns e.Err.
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// rawError is used by providers to report raw input which value failed to
+// Set without changing error message.
+type rawError struct {
+	raw string
+	err error
+}
+
+// newRawError returns err with raw input (masked if value is secret).
+func newRawError(value Value, raw string, err error) error {
+	if isSecret(value) {
+		raw = secretMask
+	}
+	return &rawError{raw: raw, err: err}
+}
+
+// Error implements error interface.
+func (e *rawError) Error() string { return e.err.Error() }
+
+// Unwrap returns e.err.
+func (e *rawError) Unwrap() error { return e.err }
+
+// FieldErrors returns all FieldError found in err, including errors
+// joined by [errors.Join]. Returns nil if there are no FieldError in err.
+func FieldErrors(err error) []*FieldError {
+	switch e := err.(type) { //nolint:errorlint // Walking error tree.
+	case *FieldError:
+		return []*FieldError{e}
+	case interface{ Unwrap() []error }:
+		var errs []*FieldError
+		for _, wrapped := range e.Unwrap() {
+			errs = append(errs, FieldErrors(wrapped)...)
+		}
+		return errs
+	case interface{ Unwrap() error }:
+		return FieldErrors(e.Unwrap())
+	}
+	return nil
 }
 
 // RequiredError is returned from Value(&err) methods if value wasn't set.
@@ -48,80 +140,269 @@ type RequiredError struct{ Value }
 // Error implements error interface.
 func (*RequiredError) Error() string { return "value required" }
 
-// WrapErr adds more details about err.Value (if err is a RequiredError)
-// by looking for related flag name and field name/tags in given fs and
-// cfgs, otherwise returns err as is.
+// WrapErr adds more details about err.Value (if err is a RequiredError
+// or ValueError) by looking for related flag name and field name/tags in
+// given fs and cfgs, otherwise returns err as is.
+//
+// Returned error is a FieldError. If err.Value is not found in cfgs then
+// FieldError will have empty Field and Tags. If err contains several
+// errors joined by [errors.Join] then each of them will be handled this
+// way and results will be joined. Errors which already contain
+// FieldError are returned as is.
 func WrapErr(err error, fs *flag.FlagSet, cfgs ...any) error {
-	reqErr := new(RequiredError)
-	if errors.As(err, &reqErr) {
-		var flagName string
+	return fieldError(err, flagNameIn(fs), cfgs...)
+}
+
+// WrapPErr is like WrapErr but for [pflag.FlagSet].
+func WrapPErr(err error, fs *pflag.FlagSet, cfgs ...any) error {
+	return fieldError(err, pflagNameIn(fs), cfgs...)
+}
+
+// flagNameIn returns func which returns name (like "-port") of a flag
+// defined in fs for value or empty string.
+func flagNameIn(fs *flag.FlagSet) func(Value) string {
+	return func(value Value) (flagName string) {
 		if fs != nil {
 			fs.VisitAll(func(f *flag.Flag) {
-				if f.Value == reqErr.Value {
+				if f.Value == value {
 					flagName = "-" + f.Name
 				}
 			})
 		}
-		return doWrapErr(reqErr, flagName, cfgs...)
+		return flagName
 	}
-	return err
 }
 
-// WrapPErr adds more details about err.Value (if err is a RequiredError)
-// by looking for related flag name and field name/tags in given fs and
-// cfgs, otherwise returns err as is.
-func WrapPErr(err error, fs *pflag.FlagSet, cfgs ...any) error {
-	reqErr := new(RequiredError)
-	if errors.As(err, &reqErr) {
-		var flagName string
+// pflagNameIn is like flagNameIn but for [pflag.FlagSet].
+func pflagNameIn(fs *pflag.FlagSet) func(Value) string {
+	return func(value Value) (flagName string) {
 		if fs != nil {
 			fs.VisitAll(func(f *pflag.Flag) {
-				if f.Value == reqErr.Value {
+				if f.Value == value {
 					flagName = "--" + f.Name
 				}
 			})
 		}
-		return doWrapErr(reqErr, flagName, cfgs...)
+		return flagName
 	}
-	return err
 }
 
-func doWrapErr(reqErr *RequiredError, flagName string, cfgs ...any) error {
-	var lastErr error
+// fieldError implements WrapErr using flagName to get flag name.
+// RequiredError is used as FieldError.Err as is, without errors which
+// wrap it, ValueError is used together with errors which wrap it.
+func fieldError(err error, flagName func(Value) string, cfgs ...any) error {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok { //nolint:errorlint // Walking error tree.
+		errs := joined.Unwrap()
+		wrapped := make([]error, len(errs))
+		for i := range errs {
+			wrapped[i] = fieldError(errs[i], flagName, cfgs...)
+		}
+		return errors.Join(wrapped...)
+	}
+	if fieldErr := new(FieldError); err == nil || errors.As(err, &fieldErr) {
+		return err
+	}
+	var value Value
+	if valueErr := new(ValueError); errors.As(err, &valueErr) {
+		value = valueErr.Value
+	} else if reqErr := new(RequiredError); errors.As(err, &reqErr) {
+		value, err = reqErr.Value, reqErr
+	} else {
+		return err
+	}
+	fieldErr := &FieldError{Flag: flagName(value), Err: err}
 	for _, cfg := range cfgs {
-		forStruct(cfg, func(value Value, name string, tags Tags) {
-			if value == reqErr.Value {
-				lastErr = fmt.Errorf("%s: %w", field(name, flagName, tags), reqErr)
+		forStruct(cfg, func(v Value, name string, tags Tags) {
+			if v == value {
+				fieldErr.Field, fieldErr.Tags = name, tags
 			}
 		})
 	}
-	if lastErr == nil {
-		panic("required value not found in cfgs")
-	}
-	return lastErr
+	return fieldErr
 }
 
+// VisitFields calls fn for each field of cfg (see ProvideStruct) with
+// field's value, name (like "DB.Host") and tags. It may be used to
+// integrate cfg with other packages.
+//
+// Fields of nested structs referenced by nil pointers are skipped.
+func VisitFields(cfg any, fn func(value Value, name string, tags Tags)) {
+	forStruct(cfg, fn)
+}
+
+// forStruct calls handle for each field of cfg, skipping fields of nested
+// structs referenced by nil pointers. It does not modify cfg.
 func forStruct(cfg any, handle func(Value, string, Tags)) {
+	forFields(structValue(cfg), "", nil, false, handle)
+}
+
+// forStructNew is like forStruct but sets nil pointers to nested structs
+// to new zero structs instead of skipping them.
+func forStructNew(cfg any, handle func(Value, string, Tags)) {
+	forFields(structValue(cfg), "", nil, true, handle)
+}
+
+func structValue(cfg any) reflect.Value {
 	val := reflect.ValueOf(cfg)
 	typ := val.Type()
 	if typ.Kind() != reflect.Pointer || typ.Elem().Kind() != reflect.Struct {
 		panic("cfg: must be a ptr to struct")
 	}
-	typ = typ.Elem()
+	return val.Elem()
+}
 
+func forFields(val reflect.Value, path string, parent *fieldTags, alloc bool, handle func(Value, string, Tags)) {
+	typ := val.Type()
 	for i := range typ.NumField() {
 		f := typ.Field(i)
-		if f.PkgPath != "" {
+		f.Tag = structTag(f)
+		embedded := f.Anonymous && !implementsValue(f.Type)
+		if f.PkgPath != "" && !(embedded && f.Type.Kind() == reflect.Struct && isNested(f, nil)) {
 			continue
 		}
-		if !implementsValue(f.Type) {
-			panic(fmt.Sprintf("cfg.%s: must implements Value", f.Name))
+		name, prefix := path+f.Name, path+f.Name+"."
+		if embedded {
+			prefix = path
+		}
+		tags := &fieldTags{tag: f.Tag, parent: parent}
+		fieldVal := val.Field(i)
+		switch {
+		case implementsValue(f.Type):
+			value := fieldVal.Addr().Interface().(Value) //nolint:forcetypeassert // Want panic.
+			handle(value, name, tags)
+		case !isNested(f, nil):
+			panic(fmt.Sprintf("cfg.%s: must implements Value", path+f.Name))
+		case f.Type.Kind() == reflect.Struct:
+			forFields(fieldVal, prefix, tags, alloc, handle)
+		case fieldVal.IsNil() && !alloc: // Skip.
+		default:
+			if fieldVal.IsNil() {
+				fieldVal.Set(reflect.New(f.Type.Elem()))
+			}
+			forFields(fieldVal.Elem(), prefix, tags, alloc, handle)
+		}
+	}
+}
+
+// structTag returns f.Tag with newlines and tabs replaced by spaces, to
+// support multiline tags.
+func structTag(f reflect.StructField) reflect.StructTag {
+	tag := strings.ReplaceAll(string(f.Tag), "\n", " ")
+	tag = strings.ReplaceAll(tag, "\t", " ")
+	return reflect.StructTag(tag)
+}
+
+// isNested reports whether field f is a nested struct (or a pointer to
+// struct) which should be handled recursively: it must have a tag like
+// "envPrefix" or contain fields implementing Value (possibly in own nested
+// structs). Types in seen are ignored to avoid endless recursion.
+func isNested(f reflect.StructField, seen map[reflect.Type]bool) bool {
+	typ := f.Type
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || seen[typ] {
+		return false
+	}
+	hasPrefix := func(key string) bool { return strings.HasSuffix(key, "Prefix") }
+	if slices.ContainsFunc(tagKeys(structTag(f)), hasPrefix) {
+		return true
+	}
+	if seen == nil {
+		seen = make(map[reflect.Type]bool)
+	}
+	seen[typ] = true
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		switch {
+		case f.PkgPath != "" && !(f.Anonymous && f.Type.Kind() == reflect.Struct):
+		case implementsValue(f.Type):
+			if f.PkgPath == "" {
+				return true
+			}
+		case isNested(f, seen):
+			return true
+		}
+	}
+	return false
+}
+
+// fieldTags implements Tags for a field of (probably nested) struct.
+// Value of each tag "key" is prefixed with values of tags "keyPrefix"
+// of all parent struct fields.
+type fieldTags struct {
+	tag    reflect.StructTag
+	parent *fieldTags
+}
+
+// Get implements Tags interface.
+func (t *fieldTags) Get(key string) string {
+	value, _ := t.Lookup(key)
+	return value
+}
+
+// Lookup implements Tags interface.
+func (t *fieldTags) Lookup(key string) (value string, ok bool) {
+	value, ok = t.tag.Lookup(key)
+	if !ok {
+		return "", false
+	}
+	for p := t.parent; p != nil; p = p.parent {
+		value = p.tag.Get(key+"Prefix") + value
+	}
+	return value, true
+}
+
+// String returns all tags (with applied prefixes) in struct tag format.
+func (t *fieldTags) String() string {
+	var tags []string
+	for _, key := range tagKeys(t.tag) {
+		value, _ := t.Lookup(key)
+		tags = append(tags, key+":"+strconv.Quote(value))
+	}
+	return strings.Join(tags, " ")
+}
+
+// tagKeys returns keys of all (unique) tags in conventional format.
+func tagKeys(tag reflect.StructTag) (keys []string) {
+	// Based on reflect.StructTag.Lookup.
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		key := string(tag[:i])
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		tag = tag[i+1:]
+
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
 		}
-		f.Tag = reflect.StructTag(strings.ReplaceAll(string(f.Tag), "\n", " "))
-		f.Tag = reflect.StructTag(strings.ReplaceAll(string(f.Tag), "\t", " "))
-		value := val.Elem().FieldByName(f.Name).Addr().Interface().(Value) //nolint:forcetypeassert // Want panic.
-		handle(value, f.Name, f.Tag)
 	}
+	return keys
 }
 
 func field(name string, sources ...any) string {
@@ -139,6 +420,9 @@ func AddFlag(fs *flag.FlagSet, value flag.Value, name string, usage string) {
 		return
 	}
 	fs.Var(value, name, usage)
+	if v, ok := value.(Value); ok {
+		setFlagSource(fs, v, name)
+	}
 }
 
 // AddPFlag defines a flag with the specified name and usage string.
@@ -148,4 +432,42 @@ func AddPFlag(fs *pflag.FlagSet, value pflag.Value, name string, usage string) {
 		return
 	}
 	fs.Var(value, name, usage)
+	if v, ok := value.(Value); ok {
+		setPFlagSource(fs, v, name)
+	}
+}
+
+// AddFlags defines a flag for each field in cfg (see ProvideStruct) with
+// tag "flag", using flag name from this tag and usage string from tag
+// "usage". Calling it again with same fs and cfg will have no effect.
+func AddFlags(fs *flag.FlagSet, cfg any) {
+	forStruct(cfg, func(value Value, _ string, tags Tags) {
+		if name := tags.Get("flag"); name != "" {
+			AddFlag(fs, value, name, tags.Get("usage"))
+		}
+	})
+}
+
+// AddPFlags defines a flag for each field in cfg (see ProvideStruct) with
+// tag "flag", using flag name from this tag, shorthand from tag "short"
+// and usage string from tag "usage". Calling it again with same fs and
+// cfg will have no effect.
+//
+// Flags for values with IsBoolFlag method returning true may be used
+// without an argument (like flags defined by [pflag.FlagSet.Bool]).
+func AddPFlags(fs *pflag.FlagSet, cfg any) {
+	forStruct(cfg, func(value Value, _ string, tags Tags) {
+		name := tags.Get("flag")
+		if name == "" {
+			return
+		}
+		if f := fs.Lookup(name); f != nil && f.Value == value {
+			return
+		}
+		f := fs.VarPF(value, name, tags.Get("short"), tags.Get("usage"))
+		setPFlagSource(fs, value, name)
+		if boolFlag, ok := value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+			f.NoOptDefVal = "true"
+		}
+	})
 }
@@ -0,0 +1,691 @@
+package appcfg_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+	"github.com/spf13/pflag"
+
+	"github.com/powerman/appcfg"
+)
+
+// fromMap implements appcfg.Provider using value from map with key
+// defined by tag "key".
+type fromMap map[string]string
+
+func (m fromMap) Provide(value appcfg.Value, _ string, tags appcfg.Tags) (bool, error) {
+	key := tags.Get("key")
+	s, ok := m[key]
+	if !ok {
+		return false, nil
+	}
+	err := value.Set(s)
+	if err != nil {
+		err = fmt.Errorf("%s=%q: %w", key, s, err)
+	}
+	return true, err
+}
+
+func TestProvideStruct(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Host    appcfg.NotEmptyString `key:"host"`
+		Port    appcfg.Port           `key:"port"`
+		Retries appcfg.IntBetween     `key:"retries"`
+		Timeout appcfg.Duration       `key:"timeout"`
+	}
+	cfg.Retries = appcfg.NewIntBetween(1, 3)
+	cfg.Timeout = appcfg.MustDuration("3s")
+
+	err := appcfg.ProvideStruct(&cfg, fromMap{
+		"host":    " ",
+		"port":    "0",
+		"retries": "2",
+	})
+	t.Match(err, `^Host \(key:"host"\): host=" ": empty`)
+	t.Match(err, `(?m)^Port \(key:"port"\): port="0": not between`)
+	t.Equal(strings.Count(err.Error(), "\n"), 1)
+
+	errs := appcfg.FieldErrors(err)
+	t.Len(errs, 2)
+	t.Equal(errs[0].Field, "Host")
+	t.Equal(errs[0].Tags.Get("key"), "host")
+	t.Equal(errs[1].Field, "Port")
+	t.Equal(errs[1].Raw, "") // Unknown for custom Provider.
+	t.Equal(errs[1].Env, "")
+	t.True(errors.Is(errs[0], appcfg.ErrEmptyOrWhite))
+	t.True(errors.Is(err, appcfg.ErrNotBetween))
+	t.False(errors.Is(err, appcfg.ErrNotOneOf))
+	fieldErr := new(appcfg.FieldError)
+	t.True(errors.As(err, &fieldErr))
+	t.Equal(fieldErr.Field, "Host")
+
+	t.Nil(cfg.Host.Get())
+	t.Nil(cfg.Port.Get())
+	t.Equal(cfg.Retries.Get(), 2)
+	t.Equal(cfg.Timeout.String(), "3s")
+
+	t.Nil(appcfg.ProvideStruct(&cfg, fromMap{"host": "localhost"}))
+	t.Nil(appcfg.FieldErrors(nil))
+	t.Nil(appcfg.FieldErrors(errors.New("some")))
+	t.Len(appcfg.FieldErrors(fmt.Errorf("wrap: %w", err)), 2)
+}
+
+type commonCfg struct {
+	Debug appcfg.Bool `key:"debug"`
+}
+
+type DBCfg struct {
+	Host appcfg.NotEmptyString `key:"host"`
+	Port appcfg.Port           `key:"port"`
+}
+
+func TestProvideStructNested(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		commonCfg
+		DB    DBCfg `keyPrefix:"db."`
+		Cache *struct {
+			Host appcfg.NotEmptyString `key:"host"`
+		} `keyPrefix:"cache."`
+		Replica struct {
+			DBCfg `keyPrefix:"replica."`
+		} `keyPrefix:"db."`
+	}
+
+	err := appcfg.ProvideStruct(&cfg, fromMap{
+		"debug":           "true",
+		"db.host":         "localhost",
+		"db.port":         "0",
+		"cache.host":      "cache",
+		"host":            "wrong",
+		"db.replica.host": "replica",
+		"db.replica.port": "5432",
+	})
+	t.Match(err, `^DB.Port \(key:"db.port"\): db.port="0": not between`)
+	t.Equal(cfg.Debug.String(), "true")
+	t.Equal(cfg.DB.Host.String(), "localhost")
+	t.NotNil(cfg.Cache)
+	t.Equal(cfg.Cache.Host.String(), "cache")
+	t.Equal(cfg.Replica.Host.String(), "replica")
+	t.Equal(cfg.Replica.Port.String(), "5432")
+
+	var names []string
+	appcfg.VisitFields(&cfg, func(_ appcfg.Value, name string, _ appcfg.Tags) {
+		names = append(names, name)
+	})
+	t.DeepEqual(names, []string{"Debug", "DB.Host", "DB.Port", "Cache.Host", "Replica.Host", "Replica.Port"})
+
+	_ = cfg.DB.Port.Value(&err)
+	err = appcfg.WrapErr(err, nil, &cfg)
+	t.Match(err, `^DB.Port \(key:"db.port"\): value required`)
+
+	var notInCfg appcfg.Int
+	_ = notInCfg.Value(&err)
+	t.Equal(appcfg.WrapErr(err, nil, &cfg).Error(), "value required")
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	appcfg.AddFlag(fs, &notInCfg, "n", "")
+	err = appcfg.WrapErr(fmt.Errorf("wrapped: %w", err), fs, &cfg)
+	t.Equal(err.Error(), "-n: value required")
+	fieldErr := new(appcfg.FieldError)
+	t.True(errors.As(err, &fieldErr))
+	t.Equal(fieldErr.Field, "")
+	t.Equal(fieldErr.Flag, "-n")
+	t.Equal(appcfg.WrapErr(err, fs, &cfg), err)
+
+	var err1, err2 error
+	_ = cfg.DB.Port.Value(&err1)
+	_ = notInCfg.Value(&err2)
+	err = appcfg.WrapPErr(errors.Join(err1, io.EOF, err2), nil, &cfg)
+	t.Equal(err.Error(), `DB.Port (key:"db.port"): value required
+EOF
+value required`)
+	t.Len(appcfg.FieldErrors(err), 2)
+	t.True(errors.Is(err, io.EOF))
+
+	err = appcfg.WrapErr(fmt.Errorf("check: %w", &appcfg.ValueError{Value: &cfg.DB.Port, Err: io.EOF}), nil, &cfg)
+	t.Equal(err.Error(), `DB.Port (key:"db.port"): check: EOF`)
+	t.True(errors.Is(err, io.EOF))
+
+	var bad struct {
+		DB struct {
+			Host appcfg.String
+			Port int
+		}
+	}
+	t.PanicMatch(func() { _ = appcfg.ProvideStruct(&bad) }, `cfg.DB.Port: must implements Value`)
+	var badStruct struct {
+		T time.Time
+	}
+	t.PanicMatch(func() { _ = appcfg.ProvideStruct(&badStruct) }, `cfg.T: must implements Value`)
+	var badPtr struct {
+		Log *slog.Logger
+	}
+	t.PanicMatch(func() { appcfg.VisitFields(&badPtr, nil) }, `cfg.Log: must implements Value`)
+	t.Nil(badPtr.Log)
+}
+
+func TestProvideStructNil(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		DB    *DBCfg
+		Cache *struct{} `keyPrefix:"cache."`
+	}
+	var names []string
+	appcfg.VisitFields(&cfg, func(_ appcfg.Value, name string, _ appcfg.Tags) {
+		names = append(names, name)
+	})
+	t.Len(names, 0)
+	_ = appcfg.Docs("", &cfg)
+	_ = appcfg.JSONSchema("key", &cfg)
+	_ = appcfg.Sources(&cfg)
+	t.Nil(cfg.DB)
+	t.Nil(cfg.Cache)
+
+	t.Nil(appcfg.ProvideStruct(&cfg, fromMap{"host": "localhost"}))
+	t.NotNil(cfg.DB)
+	t.NotNil(cfg.Cache)
+	t.Equal(cfg.DB.Host.String(), "localhost")
+}
+
+func TestAddFlags(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Host  appcfg.NotEmptyString `flag:"host" short:"H" usage:"host to connect"`
+		Debug appcfg.Bool           `flag:"debug" short:"d"`
+		NoTag appcfg.String
+		DB    struct {
+			Port appcfg.Port `flag:"port" usage:"port to connect"`
+		} `flagPrefix:"db."`
+	}
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	appcfg.AddFlags(fs, &cfg)
+	appcfg.AddFlags(fs, &cfg)
+	t.Equal(fs.Lookup("host").Usage, "host to connect")
+	t.NotNil(fs.Lookup("db.port"))
+	t.Nil(fs.Lookup("NoTag"))
+	t.Nil(fs.Parse([]string{"-host=localhost", "-debug", "-db.port=80"}))
+	t.Equal(cfg.Host.String(), "localhost")
+	t.Equal(cfg.Debug.String(), "true")
+	t.Equal(cfg.DB.Port.String(), "80")
+
+	pfs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	appcfg.AddPFlags(pfs, &cfg)
+	appcfg.AddPFlags(pfs, &cfg)
+	t.Equal(pfs.Lookup("host").Usage, "host to connect")
+	t.Equal(pfs.Lookup("host").Shorthand, "H")
+	t.Nil(pfs.Lookup("NoTag"))
+	t.Nil(pfs.Parse([]string{"-H", "example.com", "--debug=false", "--db.port", "443"}))
+	t.Equal(cfg.Host.String(), "example.com")
+	t.Equal(cfg.Debug.String(), "false")
+	t.Equal(cfg.DB.Port.String(), "443")
+	t.Nil(pfs.Parse([]string{"-d"}))
+	t.Equal(cfg.Debug.String(), "true")
+}
+
+func TestSources(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Host    appcfg.String      `env:"HOST" flag:"host"`
+		Port    appcfg.Port        `env:"PORT" flag:"port"`
+		Hosts   appcfg.StringSlice `json:"hosts"`
+		Retries appcfg.Int         `key:"retries"`
+		Timeout appcfg.Duration
+		Debug   appcfg.Bool
+	}
+	cfg.Timeout = appcfg.MustDuration("3s")
+
+	fromDotenv, err := appcfg.NewFromDotenv(strings.NewReader("APP_HOST=localhost\nAPP_PORT=80\n"), "APP_")
+	t.Nil(err)
+	fromJSON, err := appcfg.NewFromJSON(strings.NewReader(`{"hosts":["a","b"]}`))
+	t.Nil(err)
+	t.Nil(appcfg.ProvideStruct(&cfg, fromDotenv, fromJSON, fromMap{"retries": "3"}))
+
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	appcfg.AddFlags(fs, &cfg)
+	t.Nil(fs.Parse([]string{"-port=443"}))
+
+	t.Equal(appcfg.SourceOf(&cfg.Host), appcfg.Source{Kind: appcfg.SourceFile, Key: ".env:$APP_HOST", Raw: "localhost"})
+	t.Equal(appcfg.SourceOf(&cfg.Port), appcfg.Source{Kind: appcfg.SourceFlag, Key: "-port", Raw: "443"})
+	t.Equal(appcfg.SourceOf(&cfg.Hosts), appcfg.Source{Kind: appcfg.SourceFile, Key: "JSON:hosts", Raw: "a,b"})
+	t.Equal(appcfg.SourceOf(&cfg.Retries).Kind, "appcfg_test.fromMap")
+	t.Equal(appcfg.SourceOf(&cfg.Timeout), appcfg.Source{Kind: appcfg.SourceDefault, Raw: "3s"})
+	t.Equal(appcfg.SourceOf(&cfg.Debug), appcfg.Source{Kind: appcfg.SourceUnset})
+
+	var buf strings.Builder
+	t.Nil(appcfg.DumpSources(&buf, &cfg))
+	t.Equal(buf.String(), `Host: file .env:$APP_HOST="localhost"
+Port: flag -port="443"
+Hosts: file JSON:hosts="a,b"
+Retries: appcfg_test.fromMap "3"
+Timeout: default "3s"
+Debug: unset
+`)
+
+	t.Nil(cfg.Host.Set("example.com"))
+	t.Equal(appcfg.SourceOf(&cfg.Host), appcfg.Source{Kind: appcfg.SourceDefault, Raw: "example.com"})
+	cfg.Port = appcfg.Port{}
+	t.Nil(cfg.Port.Set("81"))
+	t.Equal(appcfg.SourceOf(&cfg.Port), appcfg.Source{Kind: appcfg.SourceDefault, Raw: "81"})
+}
+
+func TestSourcesCollected(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	type config struct {
+		Host appcfg.String `env:"HOST" flag:"host"`
+	}
+	collected := make(chan struct{})
+	func() {
+		cfg := new(config)
+		runtime.AddCleanup(cfg, func(ch chan struct{}) { close(ch) }, collected)
+		fromDotenv, err := appcfg.NewFromDotenv(strings.NewReader("HOST=localhost\n"), "")
+		t.Nil(err)
+		t.Nil(appcfg.LoadFlags(cfg, flag.NewFlagSet("", flag.ContinueOnError), nil, fromDotenv))
+		t.Equal(appcfg.SourceOf(&cfg.Host).Kind, appcfg.SourceFile)
+	}()
+	for range 10 {
+		runtime.GC()
+		select {
+		case <-collected:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Fail()
+}
+
+func TestValidate(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	type tlsCfg struct {
+		Mode appcfg.OneOfString `env:"MODE"`
+		Cert appcfg.String      `env:"CERT" required_if:"Mode=tls Mode=mtls"`
+		CA   appcfg.String      `env:"CA" required_if:"Mode=mtls"`
+	}
+	var cfg struct {
+		Host    appcfg.String `env:"HOST" flag:"host" required:"true"`
+		Port    appcfg.Port   `env:"PORT" required:"false"`
+		Socket  appcfg.String `env:"SOCKET" required_unless:"Port"`
+		Debug   appcfg.Bool   `required_if:"TLS.Cert"`
+		TLS     tlsCfg        `envPrefix:"TLS_"`
+		Timeout appcfg.Duration
+	}
+	cfg.TLS.Mode = appcfg.NewOneOfString([]string{"", "tls", "mtls"})
+
+	err := appcfg.Validate(&cfg)
+	t.Match(err, `^Host \(env:"HOST" flag:"host" required:"true"\): value required`)
+	t.Match(err, `(?m)^Socket \(env:"SOCKET" required_unless:"Port"\): value required$`)
+	errs := appcfg.FieldErrors(err)
+	t.Len(errs, 2)
+	reqErr := new(appcfg.RequiredError)
+	t.True(errors.As(errs[0], &reqErr))
+	t.Equal(reqErr.Value, &cfg.Host)
+
+	t.Nil(cfg.Host.Set("localhost"))
+	t.Nil(cfg.Port.Set("80"))
+	t.Nil(cfg.TLS.Mode.Set("mtls"))
+	err = appcfg.Validate(&cfg)
+	t.Match(err, `^TLS.Cert \(env:"TLS_CERT" required_if:"Mode=tls Mode=mtls"\): value required`)
+	t.Match(err, `(?m)^TLS.CA \(env:"TLS_CA" required_if:"Mode=mtls"\): value required$`)
+	t.Len(appcfg.FieldErrors(err), 2)
+
+	t.Nil(cfg.TLS.Mode.Set("tls"))
+	t.Nil(cfg.TLS.Cert.Set("cert.pem"))
+	err = appcfg.Validate(&cfg)
+	t.Match(err, `^Debug \(required_if:"TLS.Cert"\): value required$`)
+
+	var badRequired struct {
+		Host appcfg.String `required:"yes"`
+	}
+	t.PanicMatch(func() { _ = appcfg.Validate(&badRequired) }, `cfg.Host: required: .*invalid syntax`)
+	var badCondition struct {
+		Port appcfg.Port `required_if:"Mode=tls"`
+	}
+	t.PanicMatch(func() { _ = appcfg.Validate(&badCondition) }, `cfg.Port: unknown field "Mode" in condition`)
+}
+
+type tlsCfg struct {
+	Host     appcfg.String `env:"HOST" flag:"host" required:"true"`
+	TLSCert  appcfg.String `env:"TLS_CERT" flag:"tls-cert"`
+	TLSKey   appcfg.String `env:"TLS_KEY" flag:"tls-key"`
+	MinConns appcfg.Int    `env:"MIN_CONNS" default:"1"`
+	MaxConns appcfg.Int    `env:"MAX_CONNS" default:"10"`
+}
+
+var (
+	errBothOrNone = errors.New("must be set together with TLSCert")
+	errTooBig     = errors.New("too big")
+)
+
+func (c *tlsCfg) Validate() error {
+	var errs []error
+	if (c.TLSCert.Get() == nil) != (c.TLSKey.Get() == nil) {
+		errs = append(errs, &appcfg.ValueError{Value: &c.TLSKey, Err: errBothOrNone})
+	}
+	var err error
+	if c.MinConns.Value(&err) > c.MaxConns.Value(&err) {
+		errs = append(errs, fmt.Errorf("MinConns > MaxConns: %w", &appcfg.ValueError{Value: &c.MinConns, Err: errTooBig}))
+	}
+	return errors.Join(append(errs, err)...)
+}
+
+func TestLoadFlags(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg tlsCfg
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	err := appcfg.LoadFlags(&cfg, fs, nil, fromMap{}, appcfg.NewFromDefault())
+	t.Match(err, `^Host \(-host env:"HOST" flag:"host" required:"true"\): value required$`)
+
+	cfg = tlsCfg{}
+	fs = flag.NewFlagSet("", flag.ContinueOnError)
+	err = appcfg.LoadFlags(&cfg, fs, []string{"-host=localhost", "-tls-cert=cert.pem"}, fromMap{})
+	t.Match(err, `^TLSKey \(-tls-key env:"TLS_KEY" flag:"tls-key"\): must be set together with TLSCert`)
+	t.Match(err, `(?m)^MaxConns \(env:"MAX_CONNS" default:"10"\): value required$`)
+	t.Len(appcfg.FieldErrors(err), 2)
+	t.True(errors.Is(err, errBothOrNone))
+
+	t.Nil(cfg.TLSKey.Set("key.pem"))
+	t.Nil(cfg.MinConns.Set("20"))
+	t.Nil(cfg.MaxConns.Set("10"))
+	t.Match(appcfg.LoadFlags(&cfg, nil, nil), `^MinConns \(env:"MIN_CONNS" default:"1"\): MinConns > MaxConns: too big$`)
+
+	cfg = tlsCfg{}
+	pfs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	err = appcfg.LoadPFlags(&cfg, pfs, []string{"--host=localhost", "--tls-key=key.pem"}, appcfg.NewFromDefault())
+	t.Match(err, `^TLSKey \(--tls-key env:"TLS_KEY" flag:"tls-key"\): must be set together with TLSCert$`)
+	t.Len(appcfg.FieldErrors(err), 1)
+
+	cfg = tlsCfg{}
+	err = appcfg.LoadPFlags(&cfg, pflag.NewFlagSet("", pflag.ContinueOnError), []string{"--host=localhost"}, appcfg.NewFromDefault())
+	t.Nil(err)
+	t.Equal(cfg.MaxConns.String(), "10")
+
+	cfg = tlsCfg{}
+	fs = flag.NewFlagSet("", flag.ContinueOnError)
+	appcfg.AddFlags(fs, &cfg)
+	appcfg.AddPFlags(pflag.NewFlagSet("", pflag.ContinueOnError), &cfg)
+	err = appcfg.LoadFlags(&cfg, fs, nil)
+	t.Match(err, `^Host \(-host env:"HOST" flag:"host" required:"true"\): value required$`)
+}
+
+func TestDocs(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Host     appcfg.String       `env:"HOST" flag:"host" default:"localhost" usage:"host to | connect"`
+		Port     appcfg.Port         `env:"PORT" required:"true" desc:"port to connect"`
+		Password appcfg.SecretString `env:"PASSWORD" required_if:"Host"`
+		Greeting appcfg.String       `env:"GREETING"`
+		Level    appcfg.OneOfString  `flag:"level"`
+		Ports    appcfg.PortSlice    `env:"PORTS"`
+		DB       struct {
+			Name appcfg.NotEmptyString `env:"NAME" default:"app"`
+		} `envPrefix:"DB_"`
+	}
+	cfg.Ports = appcfg.MustPortSlice("80", "443")
+	cfg.Password = appcfg.MustSecretString("s3cret")
+	cfg.Greeting = appcfg.MustString("Hello, \"$USER\"")
+	cfg.Level = appcfg.MustOneOfString("info", []string{"debug", "info"})
+	pfs := pflag.NewFlagSet("", pflag.ContinueOnError)
+	appcfg.AddPFlags(pfs, &cfg)
+
+	docs := appcfg.Docs("APP_", &cfg)
+	t.Len(docs, 7)
+	t.Equal(docs[0], appcfg.FieldDoc{
+		Field:   "Host",
+		Env:     "APP_HOST",
+		Flag:    "--host",
+		Type:    "String",
+		Default: "localhost",
+		Usage:   "host to | connect",
+	})
+	t.Equal(docs[1].Usage, "port to connect")
+	t.Equal(docs[1].Required, "yes")
+	t.Equal(docs[2].Required, "if Host")
+	t.Equal(docs[2].Default, "******")
+	t.True(docs[2].Secret)
+	t.Equal(docs[4].Flag, "--level")
+	t.Equal(docs[4].Env, "")
+	t.Equal(docs[5].Default, "80,443")
+	t.Equal(docs[6].Field, "DB.Name")
+	t.Equal(docs[6].Env, "APP_DB_NAME")
+
+	var buf strings.Builder
+	t.Nil(appcfg.WriteMarkdown(&buf, "APP_", &cfg))
+	t.Equal(buf.String(), "| Field | Env | Flag | Type | Default | Required | Description |\n"+
+		"|-------|-----|------|------|---------|----------|-------------|\n"+
+		"| Host | `APP_HOST` | `--host` | String | `localhost` |  | host to \\| connect |\n"+
+		"| Port | `APP_PORT` |  | Port |  | yes | port to connect |\n"+
+		"| Password | `APP_PASSWORD` |  | SecretString | `******` | if Host |  |\n"+
+		"| Greeting | `APP_GREETING` |  | String | `Hello, \"$USER\"` |  |  |\n"+
+		"| Level |  | `--level` | OneOfString | `info` |  |  |\n"+
+		"| Ports | `APP_PORTS` |  | PortSlice | `80,443` |  |  |\n"+
+		"| DB.Name | `APP_DB_NAME` |  | NotEmptyString | `app` |  |  |\n")
+
+	buf.Reset()
+	t.Nil(appcfg.WriteText(&buf, "APP_", &cfg))
+	t.Match(buf.String(), `^FIELD +ENV +FLAG +TYPE +DEFAULT +REQUIRED +DESCRIPTION\n`)
+	t.Match(buf.String(), `\nHost +APP_HOST +--host +String +"localhost" +host to \| connect\n`)
+	t.Match(buf.String(), `\nPort +APP_PORT +Port +yes +port to connect\n`)
+
+	buf.Reset()
+	t.Nil(appcfg.WriteDotenv(&buf, "APP_", &cfg))
+	t.Equal(buf.String(), `# host to | connect
+# Type: String.
+APP_HOST=localhost
+
+# port to connect
+# Type: Port, required: yes.
+APP_PORT=
+
+# Type: SecretString, required: if Host.
+APP_PASSWORD=
+
+# Type: String.
+APP_GREETING='Hello, "$USER"'
+
+# Type: PortSlice.
+APP_PORTS=80,443
+
+# Type: NotEmptyString.
+APP_DB_NAME=app
+`)
+	fromDotenv, err := appcfg.NewFromDotenv(strings.NewReader(buf.String()), "APP_")
+	t.Nil(err)
+	v, _ := fromDotenv.Lookup("APP_GREETING")
+	t.Equal(v, cfg.Greeting.String())
+	var ports struct {
+		Ports appcfg.PortSlice `env:"PORTS"`
+	}
+	t.Nil(appcfg.ProvideStruct(&ports, fromDotenv))
+	t.DeepEqual(ports.Ports.Get(), cfg.Ports.Get())
+}
+
+func TestJSONSchema(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Port     appcfg.Port           `json:"port" required:"true"`
+		Retries  appcfg.IntBetween     `json:"retries,omitempty" usage:"number of retries"`
+		Level    appcfg.OneOfString    `json:"level"`
+		Timeout  appcfg.Duration       `json:"timeout"`
+		Hosts    appcfg.EndpointSlice  `json:"hosts"`
+		Password appcfg.NotEmptySecret `json:"password"`
+		Custom   appcfg.Var[int]       `json:"custom"`
+		NoTag    appcfg.String
+		Skip     appcfg.String `json:"-"`
+		DB       struct {
+			Host appcfg.String `json:"host" required:"true"`
+			Port appcfg.Uint   `json:"port"`
+		} `jsonPrefix:"db."`
+	}
+	cfg.Retries = appcfg.NewIntBetween(1, 3)
+	cfg.Level = appcfg.NewOneOfString([]string{"debug", "info"})
+
+	buf, err := json.MarshalIndent(appcfg.JSONSchema("json", &cfg), "", "  ")
+	t.Nil(err)
+	t.Equal(string(buf), `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "properties": {
+    "custom": {},
+    "db": {
+      "properties": {
+        "host": {
+          "type": "string"
+        },
+        "port": {
+          "minimum": 0,
+          "type": "integer"
+        }
+      },
+      "required": [
+        "host"
+      ],
+      "type": "object"
+    },
+    "hosts": {
+      "items": {
+        "format": "uri",
+        "type": "string"
+      },
+      "type": "array"
+    },
+    "level": {
+      "enum": [
+        "debug",
+        "info"
+      ],
+      "type": "string"
+    },
+    "password": {
+      "pattern": "\\S",
+      "type": "string",
+      "writeOnly": true
+    },
+    "port": {
+      "maximum": 65535,
+      "minimum": 1,
+      "type": "integer"
+    },
+    "retries": {
+      "description": "number of retries",
+      "maximum": 3,
+      "minimum": 1,
+      "type": "integer"
+    },
+    "timeout": {
+      "pattern": "^[-+]?(0|((\\d+(\\.\\d*)?|\\.\\d+)(ns|us|µs|μs|ms|s|m|h))+)$",
+      "type": "string"
+    }
+  },
+  "required": [
+    "port"
+  ],
+  "type": "object"
+}`)
+
+	timeout := appcfg.JSONSchema("json", &cfg)["properties"].(map[string]any)["timeout"].(map[string]any)
+	pattern := regexp.MustCompile(timeout["pattern"].(string))
+	for _, s := range []string{"0", "30s", "1h30m", "-1.5ms", "+.5µs"} {
+		t.True(pattern.MatchString(s), s)
+	}
+	for _, s := range []string{"", "30", "s", "1d", "1.5"} {
+		t.False(pattern.MatchString(s), s)
+	}
+
+	var conflict struct {
+		Extra appcfg.String `json:"extra"`
+		Deep  appcfg.String `json:"extra.a"`
+	}
+	t.PanicMatch(func() { appcfg.JSONSchema("json", &conflict) },
+		`^cfg.Deep \(json:"extra.a"\): conflicts with cfg.Extra \(json:"extra"\)$`)
+	var reversed struct {
+		Deep  appcfg.String `json:"extra.a"`
+		Extra appcfg.String `json:"extra"`
+	}
+	t.PanicMatch(func() { appcfg.JSONSchema("json", &reversed) },
+		`^cfg.Extra \(json:"extra"\): conflicts with cfg.Deep \(json:"extra.a"\)$`)
+}
+
+func TestFlagUsage(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Host    appcfg.String      `env:"HOST" flag:"host" short:"H" default:"localhost" usage:"host to connect"`
+		Port    appcfg.Port        `env:"PORT" flag:"port" required:"true"`
+		Retries appcfg.IntBetween  `env:"RETRIES" required_if:"Host=example.com"`
+		Level   appcfg.OneOfString `flag:"level" usage:"log level"`
+		NoTag   appcfg.String
+	}
+	cfg.Retries = appcfg.MustIntBetween("3", 1, 5)
+	cfg.Level = appcfg.NewOneOfString([]string{"debug", "info"})
+
+	var buf strings.Builder
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(&buf)
+	appcfg.AddFlags(fs, &cfg)
+	fs.Bool("version", false, "print version")
+	fs.Usage = appcfg.FlagUsage(fs, "APP_", &cfg)
+	t.Err(fs.Parse([]string{"-h"}), flag.ErrHelp)
+	t.Equal(buf.String(), `Usage of app:
+  -host, $APP_HOST String
+    	host to connect (default "localhost")
+  -port, $APP_PORT Port
+    	(allowed: 1..65535; required: yes)
+  $APP_RETRIES IntBetween
+    	(default "3"; allowed: 1..5; required: if Host=example.com)
+  -level OneOfString
+    	log level (allowed: debug, info)
+  -version
+    	print version
+`)
+
+	buf.Reset()
+	pfs := pflag.NewFlagSet("app", pflag.ContinueOnError)
+	pfs.SetOutput(&buf)
+	appcfg.AddPFlags(pfs, &cfg)
+	pfs.Int("workers", 4, "number of `workers`")
+	pfs.Usage = appcfg.PFlagUsage(pfs, "APP_", &cfg)
+	t.Err(pfs.Parse([]string{"--help"}), pflag.ErrHelp)
+	t.Equal(buf.String(), `Usage of app:
+  -H, --host, $APP_HOST String
+    	host to connect (default "localhost")
+  --port, $APP_PORT Port
+    	(allowed: 1..65535; required: yes)
+  $APP_RETRIES IntBetween
+    	(default "3"; allowed: 1..5; required: if Host=example.com)
+  --level OneOfString
+    	log level (allowed: debug, info)
+  --workers workers
+    	number of workers (default "4")
+`)
+}
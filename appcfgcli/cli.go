@@ -0,0 +1,73 @@
+// Package appcfgcli integrates appcfg config structs with
+// [github.com/urfave/cli/v2].
+//
+// It's a separate module, so urfave/cli is not a dependency of appcfg.
+package appcfgcli
+
+import (
+	"github.com/urfave/cli/v2"
+
+	"github.com/powerman/appcfg"
+)
+
+// Flags returns flag for each field of cfgs (see [appcfg.ProvideStruct])
+// with tag "flag". Flag will have alias defined by tag "short", usage
+// defined by tag "usage" or "desc" and environment variable defined by
+// tag "env" with envPrefix (same as used for [appcfg.FromEnv]).
+//
+// Current field values (e.g. set by [appcfg.ProvideStruct] or
+// [appcfg.FromDefault]) will be shown in help as defaults.
+func Flags(envPrefix string, cfgs ...any) []cli.Flag {
+	var flags []cli.Flag
+	for _, cfg := range cfgs {
+		appcfg.VisitFields(cfg, func(value appcfg.Value, _ string, tags appcfg.Tags) {
+			name := tags.Get("flag")
+			if name == "" {
+				return
+			}
+			f := &cli.GenericFlag{
+				Name:  name,
+				Usage: tags.Get("usage"),
+				Value: value,
+			}
+			if f.Usage == "" {
+				f.Usage = tags.Get("desc")
+			}
+			if short := tags.Get("short"); short != "" {
+				f.Aliases = []string{short}
+			}
+			if env := tags.Get("env"); env != "" {
+				f.EnvVars = []string{envPrefix + env}
+			}
+			flags = append(flags, f)
+		})
+	}
+	return flags
+}
+
+// Action returns action which checks cfgs (see [appcfg.LoadFlags]) and
+// then calls given action. Returned errors are decorated by WrapErr.
+func Action(action cli.ActionFunc, cfgs ...any) cli.ActionFunc {
+	return func(ctx *cli.Context) error {
+		for _, cfg := range cfgs {
+			err := appcfg.LoadFlags(cfg, nil, nil)
+			if err != nil {
+				return WrapErr(err, cfg)
+			}
+		}
+		return WrapErr(action(ctx), cfgs...)
+	}
+}
+
+// WrapErr works like [appcfg.WrapErr] for flags returned by Flags. It
+// also adds flag name to all [appcfg.FieldError] in err which have no
+// flag name.
+func WrapErr(err error, cfgs ...any) error {
+	err = appcfg.WrapErr(err, nil, cfgs...)
+	for _, fieldErr := range appcfg.FieldErrors(err) {
+		if fieldErr.Flag == "" && fieldErr.Tags != nil && fieldErr.Tags.Get("flag") != "" {
+			fieldErr.Flag = "--" + fieldErr.Tags.Get("flag")
+		}
+	}
+	return err
+}
@@ -0,0 +1,94 @@
+package appcfgcli_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/powerman/check"
+	"github.com/urfave/cli/v2"
+
+	"github.com/powerman/appcfg"
+	"github.com/powerman/appcfg/appcfgcli"
+)
+
+type config struct {
+	Host    appcfg.String `env:"HOST" flag:"host" short:"H" usage:"host to connect"`
+	Port    appcfg.Port   `env:"PORT" flag:"port" required:"true"`
+	Retries appcfg.Int    `env:"RETRIES" desc:"number of retries"`
+	Timeout appcfg.Duration
+}
+
+func newApp(t *check.C) (*cli.App, *config, *bool) {
+	t.Helper()
+	var (
+		cfg config
+		ran bool
+	)
+	t.Nil(appcfg.ProvideStruct(&cfg, appcfg.NewFromDefault()))
+	cfg.Host = appcfg.MustString("localhost")
+	app := &cli.App{
+		Name:   "app",
+		Writer: io.Discard,
+		Flags:  appcfgcli.Flags("APP_", &cfg),
+		Action: appcfgcli.Action(func(*cli.Context) error {
+			ran = true
+			var err error
+			_ = cfg.Timeout.Value(&err)
+			return err
+		}, &cfg),
+	}
+	return app, &cfg, &ran
+}
+
+func TestFlags(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	app, _, _ := newApp(t)
+	t.Len(app.Flags, 2)
+	f := app.Flags[0].(*cli.GenericFlag)
+	t.Equal(f.Name, "host")
+	t.DeepEqual(f.Aliases, []string{"H"})
+	t.DeepEqual(f.EnvVars, []string{"APP_HOST"})
+	t.Equal(f.Usage, "host to connect")
+
+	var help strings.Builder
+	app.Writer = &help
+	t.Nil(app.Run([]string{"app", "--help"}))
+	t.Match(help.String(), `--host value, -H value\s+host to connect \(default: localhost\) \[\$APP_HOST\]`)
+}
+
+func TestAction(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	app, _, ran := newApp(t)
+	t.Match(app.Run([]string{"app"}), `^Port \(--port env:"PORT" flag:"port" required:"true"\): value required$`)
+	t.False(*ran)
+
+	app, cfg, ran := newApp(t)
+	err := app.Run([]string{"app", "-H", "example.com", "--port", "80"})
+	t.True(*ran)
+	t.Match(err, `^Timeout: value required$`)
+	reqErr := new(appcfg.RequiredError)
+	t.True(errors.As(err, &reqErr))
+	t.Equal(reqErr.Value, &cfg.Timeout)
+	t.Equal(cfg.Host.String(), "example.com")
+	t.Equal(cfg.Port.String(), "80")
+}
+
+func TestWrapErr(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg config
+	var err error
+	_ = cfg.Host.Value(&err)
+	t.Match(appcfgcli.WrapErr(err, &cfg), `^Host \(--host env:"HOST" flag:"host" short:"H" usage:"host to connect"\): value required$`)
+	_ = cfg.Retries.Value(&err)
+	t.Match(appcfgcli.WrapErr(err, &cfg), `^Retries \(env:"RETRIES" desc:"number of retries"\): value required$`)
+	err = io.EOF
+	t.Equal(appcfgcli.WrapErr(err, &cfg), io.EOF)
+}
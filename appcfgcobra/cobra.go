@@ -0,0 +1,91 @@
+// Package appcfgcobra binds appcfg config structs to [cobra.Command].
+//
+// It's a separate module, so cobra is not a dependency of appcfg.
+package appcfgcobra
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/powerman/appcfg"
+)
+
+// Bind updates cfg using given providers (see [appcfg.ProvideStruct]) and
+// adds cfg fields to cmd local flags (see [appcfg.AddPFlags]), so values
+// set by providers will be shown as flag defaults in help.
+//
+// Command's RunE (or Run) is wrapped to check cfg (see [appcfg.LoadPFlags])
+// after flags are parsed, and to decorate RequiredError returned by RunE
+// (see [appcfg.WrapPErr]). So Bind must be called after setting RunE or
+// Run.
+func Bind(cmd *cobra.Command, cfg any, providers ...appcfg.Provider) error {
+	err := appcfg.ProvideStruct(cfg, providers...)
+	if err != nil {
+		return err
+	}
+	appcfg.AddPFlags(cmd.Flags(), cfg)
+	wrapRun(cmd, cfg)
+	return nil
+}
+
+// BindPersistent is like Bind but adds cfg fields to cmd persistent flags
+// and wraps RunE (or Run) of cmd and all its subcommands. So it must be
+// called after adding subcommands.
+func BindPersistent(cmd *cobra.Command, cfg any, providers ...appcfg.Provider) error {
+	err := appcfg.ProvideStruct(cfg, providers...)
+	if err != nil {
+		return err
+	}
+	appcfg.AddPFlags(cmd.PersistentFlags(), cfg)
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		wrapRun(c, cfg)
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(cmd)
+	return nil
+}
+
+// cfgsKey is a context key for cfgs bound to running command.
+type cfgsKey struct{}
+
+// wrapRun wraps RunE (or Run) of cmd to check cfg and decorate errors.
+//
+// Command may be wrapped several times (by Bind and BindPersistent),
+// so wrappers collect their cfgs in cmd context and outermost one
+// decorates errors using all cfgs bound to cmd.
+func wrapRun(cmd *cobra.Command, cfg any) {
+	runE, run := cmd.RunE, cmd.Run
+	if runE == nil && run == nil {
+		return
+	}
+	if runE == nil {
+		runE = func(cmd *cobra.Command, args []string) error {
+			run(cmd, args)
+			return nil
+		}
+	}
+	cmd.Run = nil
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		err := appcfg.LoadPFlags(cfg, nil, nil)
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if cfgs, ok := ctx.Value(cfgsKey{}).(*[]any); ok {
+			*cfgs = append(*cfgs, cfg)
+			return runE(cmd, args)
+		}
+		cfgs := []any{cfg}
+		defer cmd.SetContext(cmd.Context())
+		cmd.SetContext(context.WithValue(ctx, cfgsKey{}, &cfgs))
+		err = runE(cmd, args)
+		return appcfg.WrapPErr(err, cmd.Flags(), cfgs...)
+	}
+}
@@ -0,0 +1,129 @@
+package appcfgcobra_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/powerman/check"
+	"github.com/spf13/cobra"
+
+	"github.com/powerman/appcfg"
+	"github.com/powerman/appcfg/appcfgcobra"
+)
+
+type rootCfg struct {
+	Verbose appcfg.Bool `env:"VERBOSE" flag:"verbose" short:"v"`
+}
+
+type serveCfg struct {
+	Host    appcfg.String `env:"HOST" flag:"host" usage:"host to listen"`
+	Port    appcfg.Port   `env:"PORT" flag:"port" required:"true"`
+	Timeout appcfg.Duration
+}
+
+func newCmd(t *check.C, args ...string) (*cobra.Command, *rootCfg, *serveCfg, *bool) {
+	t.Helper()
+	var (
+		rootCfg  rootCfg
+		serveCfg serveCfg
+		ran      bool
+	)
+	root := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true}
+	serve := &cobra.Command{
+		Use: "serve",
+		RunE: func(*cobra.Command, []string) error {
+			ran = true
+			var err error
+			_ = rootCfg.Verbose.Value(&err)
+			_ = serveCfg.Timeout.Value(&err)
+			return err
+		},
+	}
+	root.AddCommand(serve)
+	root.SetOut(io.Discard)
+	root.SetArgs(args)
+	fromDotenv, err := appcfg.NewFromDotenv(strings.NewReader("APP_HOST=localhost\nAPP_VERBOSE=1"), "APP_")
+	t.Nil(err)
+	t.Nil(appcfgcobra.Bind(serve, &serveCfg, fromDotenv))
+	t.Nil(appcfgcobra.BindPersistent(root, &rootCfg, fromDotenv))
+	return root, &rootCfg, &serveCfg, &ran
+}
+
+func TestBind(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	root, _, _, _ := newCmd(t, "serve", "--help")
+	var help strings.Builder
+	root.SetOut(&help)
+	t.Nil(root.Execute())
+	t.Match(help.String(), `--host String\s+host to listen \(default localhost\)`)
+	t.Match(help.String(), `Global Flags:\n\s+-v, --verbose`)
+
+	root, _, _, ran := newCmd(t, "serve")
+	t.Match(root.Execute(), `^Port \(--port env:"PORT" flag:"port" required:"true"\): value required$`)
+	t.False(*ran)
+
+	root, rootCfg, serveCfg, ran := newCmd(t, "serve", "--port=80", "--verbose=false")
+	err := root.Execute()
+	t.True(*ran)
+	t.Match(err, `^Timeout: value required$`)
+	reqErr := new(appcfg.RequiredError)
+	t.True(errors.As(err, &reqErr))
+	t.Equal(reqErr.Value, &serveCfg.Timeout)
+	t.Equal(serveCfg.Host.String(), "localhost")
+	t.Equal(serveCfg.Port.String(), "80")
+	t.Equal(rootCfg.Verbose.String(), "false")
+}
+
+func TestBindError(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg serveCfg
+	cmd := &cobra.Command{Use: "serve", Run: func(*cobra.Command, []string) {}}
+	fromDotenv, err := appcfg.NewFromDotenv(strings.NewReader("PORT=0"), "")
+	t.Nil(err)
+	t.Match(appcfgcobra.Bind(cmd, &cfg, fromDotenv), `^Port .*"0": not between`)
+}
+
+func TestBindSeveral(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var (
+		cfg1 struct {
+			A appcfg.Int `flag:"a"`
+		}
+		cfg2 struct {
+			B appcfg.Int `flag:"b"`
+		}
+	)
+	cmd := &cobra.Command{
+		Use:           "app",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(*cobra.Command, []string) error {
+			var err1, err2 error
+			_ = cfg1.A.Value(&err1)
+			_ = cfg2.B.Value(&err2)
+			return errors.Join(err1, err2)
+		},
+	}
+	t.Nil(appcfgcobra.Bind(cmd, &cfg1))
+	t.Nil(appcfgcobra.Bind(cmd, &cfg2))
+
+	for range 2 {
+		cmd.SetArgs(nil)
+		t.Equal(cmd.Execute().Error(), "A (--a flag:\"a\"): value required\nB (--b flag:\"b\"): value required")
+	}
+	cmd.SetArgs([]string{"--a=1"})
+	t.Equal(cmd.Execute().Error(), "B (--b flag:\"b\"): value required")
+
+	cmd = &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	t.Nil(appcfgcobra.Bind(cmd, &cfg1))
+	t.Nil(cmd.RunE(cmd, nil))
+	t.Nil(cmd.Context())
+}
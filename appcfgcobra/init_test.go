@@ -0,0 +1,9 @@
+package appcfgcobra_test
+
+import (
+	"testing"
+
+	"github.com/powerman/check"
+)
+
+func TestMain(m *testing.M) { check.TestMain(m) }
@@ -32,11 +32,29 @@
 // flag and other similar packages like pflag) to be used in first
 // structure, and also some functions to help loading data from different
 // sources (like environment variables) into such Value typed values.
+// Values of any other type may be used with generic Var and VarSlice
+// (see Of and SliceOf).
 //
 // Provided Value interface has more strict semantics than [flag.Value], to
 // be able to distinguish between zero and unset values - as it is
 // important to know is required configuration value was provided or not.
 //
+// # Config files
+//
+// FromJSON, FromYAML and FromTOML provide values from a config file using
+// dotted path (like "db.host") defined by tag named after file format
+// ("json", "yaml" or "toml"). Options after comma in tag value (like
+// ",omitempty") are ignored, tag "-" is same as no tag.
+//
+// Scalars are set using their text, objects (mappings, tables) are set as
+// text in same format. Each element of array is set using separate Set
+// call, to make it possible to fill slice values (like IntSlice) - empty
+// array is set as empty string.
+//
+// Constructors which read a file from [io.Reader] (like NewFromJSON or
+// NewFromDotenv) use its Name method (like [os.File.Name]), if any, to
+// get file name for error messages.
+//
 // See example to see how to use this package, and also check
 // https://github.com/powerman/appcfg/tree/master/examples
 // to see how to test such configuration.
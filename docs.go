@@ -0,0 +1,194 @@
+package appcfg
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// FieldDoc describes cfg field for documentation.
+type FieldDoc struct {
+	Field    string // Field name (like "DB.Host").
+	Env      string // Environment variable name with prefix (from tag "env").
+	Flag     string // Flag name (like "-port" or "--port", from tag "flag").
+	Type     string // Value.Type().
+	Default  string // Current value (slice values are joined by comma) or value of tag "default".
+	Required string // "yes", "if ..." or "unless ..." (from tags "required*").
+	Allowed  string // Allowed values (like "a, b" for OneOfString or "1..3" for IntBetween).
+	Usage    string // Value of tag "usage" or "desc".
+	Secret   bool   // Value is secret (like SecretString), Default is masked.
+}
+
+// Docs returns documentation for all fields in cfg (see ProvideStruct)
+// with env tag prefixed by envPrefix (same as used for FromEnv).
+func Docs(envPrefix string, cfg any) []FieldDoc {
+	var docs []FieldDoc
+	forStruct(cfg, func(value Value, name string, tags Tags) {
+		docs = append(docs, fieldDoc(envPrefix, value, name, tags))
+	})
+	return docs
+}
+
+func fieldDoc(envPrefix string, value Value, name string, tags Tags) FieldDoc {
+	doc := FieldDoc{
+		Field:    name,
+		Flag:     flagName(value),
+		Type:     value.Type(),
+		Default:  valueText(value),
+		Required: requiredDoc(tags),
+		Usage:    tags.Get("usage"),
+		Secret:   isSecret(value),
+	}
+	if env := tags.Get("env"); env != "" {
+		doc.Env = envPrefix + env
+	}
+	if doc.Flag == "" && tags.Get("flag") != "" {
+		doc.Flag = "-" + tags.Get("flag")
+	}
+	if def, ok := tags.Lookup("default"); ok && doc.Default == "" {
+		doc.Default = def
+		if doc.Secret && def != "" {
+			doc.Default = secretMask
+		}
+	}
+	if s, ok := value.(interface{ jsonSchema() map[string]any }); ok {
+		doc.Allowed = allowedDoc(s.jsonSchema())
+	}
+	if doc.Usage == "" {
+		doc.Usage = tags.Get("desc")
+	}
+	return doc
+}
+
+// valueText returns current value in format accepted by Set if possible
+// (like "80,443" for PortSlice), otherwise returns value.String().
+func valueText(value Value) string {
+	if v, ok := value.(interface{ text() (string, bool) }); ok {
+		if s, ok := v.text(); ok {
+			return s
+		}
+	}
+	return value.String()
+}
+
+// allowedDoc returns allowed values described by JSON Schema of Value.
+func allowedDoc(schema map[string]any) string {
+	if items, ok := schema["items"].(map[string]any); ok {
+		schema = items
+	}
+	if enum, ok := schema["enum"].([]string); ok {
+		return strings.Join(enum, ", ")
+	}
+	minVal, hasMin := schema["minimum"]
+	maxVal, hasMax := schema["maximum"]
+	if hasMin && hasMax {
+		return fmt.Sprintf("%v..%v", minVal, maxVal)
+	}
+	return ""
+}
+
+func requiredDoc(tags Tags) string {
+	var docs []string
+	if required, _ := strconv.ParseBool(tags.Get("required")); required {
+		docs = append(docs, "yes")
+	}
+	if s := tags.Get("required_if"); s != "" {
+		docs = append(docs, "if "+s)
+	}
+	if s := tags.Get("required_unless"); s != "" {
+		docs = append(docs, "unless "+s)
+	}
+	return strings.Join(docs, "; ")
+}
+
+// WriteMarkdown writes documentation for all fields in cfg (see Docs) to
+// w as a Markdown table.
+func WriteMarkdown(w io.Writer, envPrefix string, cfg any) error {
+	code := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "`" + s + "`"
+	}
+	escape := strings.NewReplacer("|", `\|`, "\n", "<br>").Replace
+	var b strings.Builder
+	b.WriteString("| Field | Env | Flag | Type | Default | Required | Description |\n")
+	b.WriteString("|-------|-----|------|------|---------|----------|-------------|\n")
+	for _, doc := range Docs(envPrefix, cfg) {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			escape(doc.Field),
+			escape(code(doc.Env)),
+			escape(code(doc.Flag)),
+			escape(doc.Type),
+			escape(code(doc.Default)),
+			escape(doc.Required),
+			escape(doc.Usage),
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteText writes documentation for all fields in cfg (see Docs) to w
+// as a plain text table.
+func WriteText(w io.Writer, envPrefix string, cfg any) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tENV\tFLAG\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, doc := range Docs(envPrefix, cfg) {
+		def := doc.Default
+		if def != "" {
+			def = strconv.Quote(def)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			doc.Field, doc.Env, doc.Flag, doc.Type, def, doc.Required, doc.Usage)
+	}
+	return tw.Flush()
+}
+
+// WriteDotenv writes example .env file (see FromDotenv) for all fields in
+// cfg (see Docs) which have tag "env" to w. Each variable is preceded by
+// comment with description, type and required-ness, and set to a
+// default value (secrets are set to empty value).
+func WriteDotenv(w io.Writer, envPrefix string, cfg any) error {
+	var b strings.Builder
+	for _, doc := range Docs(envPrefix, cfg) {
+		if doc.Env == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		if doc.Usage != "" {
+			for line := range strings.Lines(doc.Usage) {
+				fmt.Fprintf(&b, "# %s\n", strings.TrimSuffix(line, "\n"))
+			}
+		}
+		fmt.Fprintf(&b, "# Type: %s", doc.Type)
+		if doc.Required != "" {
+			fmt.Fprintf(&b, ", required: %s", doc.Required)
+		}
+		b.WriteString(".\n")
+		def := doc.Default
+		if doc.Secret {
+			def = ""
+		}
+		fmt.Fprintf(&b, "%s=%s\n", doc.Env, dotenvQuote(def))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotenvQuote returns s quoted for .env file (see FromDotenv) if needed.
+func dotenvQuote(s string) string {
+	if !strings.ContainsAny(s, " \t\r\n#'\"\\$`") {
+		return s
+	}
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	return `"` + strings.NewReplacer(
+		`"`, `\"`, `\`, `\\`, `$`, `\$`, "`", "\\`", "\n", `\n`, "\r", `\r`, "\t", `\t`,
+	).Replace(s) + `"`
+}
@@ -0,0 +1,105 @@
+package appcfg
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// document is implemented by parsed documents (like JSON, YAML or TOML)
+// used by providers which look up values by dotted path.
+type document[N any] interface {
+	// lookup returns node at dotted path or false if there is no such
+	// node or it's a null.
+	lookup(path string) (N, bool)
+	// elems returns elements of node or false if node is not an array.
+	elems(node N) ([]N, bool)
+	// text returns string for scalar node or document text for other
+	// nodes.
+	text(node N) string
+	// pos returns position of node (like ":1:2") for error messages or
+	// empty string if unknown.
+	pos(node N) string
+}
+
+// provideDoc implements Provider for doc named name using dotted path
+// defined by tag key, as described in Config files section of package
+// docs.
+func provideDoc[N any](doc document[N], name, key string, value Value, tags Tags) (bool, error) {
+	path, _, _ := strings.Cut(tags.Get(key), ",")
+	if path == "" || path == "-" {
+		return false, nil
+	}
+	node, ok := doc.lookup(path)
+	if !ok {
+		return false, nil
+	}
+	set := func(path string, node N, s string) error {
+		err := value.Set(s)
+		if err != nil {
+			err = fmt.Errorf("%s%s: %s=%s: %w", name, doc.pos(node), path, quote(value, s), newRawError(value, s, err))
+		}
+		return err
+	}
+	var raw string
+	if elems, ok := doc.elems(node); ok {
+		ss := make([]string, len(elems))
+		for i, elem := range elems {
+			ss[i] = doc.text(elem)
+			err := set(fmt.Sprintf("%s[%d]", path, i), elem, ss[i])
+			if err != nil {
+				return true, err
+			}
+		}
+		raw = strings.Join(ss, ",")
+		if len(elems) == 0 {
+			err := set(path, node, "")
+			if err != nil {
+				return true, err
+			}
+		}
+	} else {
+		raw = doc.text(node)
+		if err := set(path, node, raw); err != nil {
+			return true, err
+		}
+	}
+	setSource(value, SourceFile, name+":"+path, raw)
+	return true, nil
+}
+
+// lookupPath returns value at dotted path in doc.
+func lookupPath(doc map[string]any, path string) (any, bool) {
+	var v any = doc
+	for key := range strings.SplitSeq(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// readerName returns name of r if it has method Name (like [os.File]),
+// otherwise returns def.
+func readerName(r io.Reader, def string) string {
+	if named, ok := r.(interface{ Name() string }); ok {
+		return named.Name()
+	}
+	return def
+}
+
+// readFile returns result of calling read with file opened by name.
+func readFile[T any](name string, read func(io.Reader) (T, error)) (T, error) {
+	file, err := os.Open(name) //nolint:gosec // File inclusion is a feature.
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer file.Close() //nolint:errcheck // Read-only file.
+	return read(file)
+}
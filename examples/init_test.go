@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"io"
 	"os"
@@ -8,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/powerman/check"
+
+	"github.com/powerman/appcfg"
 )
 
 func TestMain(m *testing.M) { check.TestMain(m) }
@@ -34,7 +37,7 @@ func errMatch(t *check.C, flags string, match string) {
 	t.Nil(cfg)
 }
 
-func errMatchEnv(t *check.C, name, val, match string) {
+func errIsEnv(t *check.C, name, val, field string, target error) {
 	t.Helper()
 	old, ok := os.LookupEnv(name)
 	if ok {
@@ -43,5 +46,13 @@ func errMatchEnv(t *check.C, name, val, match string) {
 		defer os.Unsetenv(name)
 	}
 	t.Nil(os.Setenv(name, val))
-	errMatch(t, "", match)
+	cfg, err := testGetCfg()
+	t.Nil(cfg)
+	t.True(errors.Is(err, target))
+	fieldErr := new(appcfg.FieldError)
+	if t.True(errors.As(err, &fieldErr)) {
+		t.Equal(fieldErr.Field, field)
+		t.Equal(fieldErr.Env, "$"+name)
+		t.Equal(fieldErr.Raw, val)
+	}
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/powerman/check"
+
+	"github.com/powerman/appcfg"
 )
 
 func Test(tt *testing.T) {
@@ -37,10 +39,10 @@ func Test(tt *testing.T) {
 		t := check.T(tt)
 		t.Setenv("EXAMPLE_RETRIES", "  1  ")
 		t.Setenv("EXAMPLE_HOST", " example.com")
-		errMatchEnv(t, "EXAMPLE_HOST", "", `^Host .* empty`)
-		errMatchEnv(t, "EXAMPLE_PORT", "0", `^Port .* between`)
-		errMatchEnv(t, "EXAMPLE_BIND_PORTS", "1,-1,2", `^BindPorts .* between`)
-		errMatchEnv(t, "EXAMPLE_RETRIES", "5", `^Retries .* between`)
+		errIsEnv(t, "EXAMPLE_HOST", "", "Host", appcfg.ErrEmptyOrWhite)
+		errIsEnv(t, "EXAMPLE_PORT", "0", "Port", appcfg.ErrNotBetween)
+		errIsEnv(t, "EXAMPLE_BIND_PORTS", "1,-1,2", "BindPorts", appcfg.ErrNotBetween)
+		errIsEnv(t, "EXAMPLE_RETRIES", "5", "Retries", appcfg.ErrNotBetween)
 		errMatch(t, "-host=", `^Host .* required`)
 		errMatch(t, "-port=", `^Port .* required`)
 		errMatch(t, "-timeout=x", `^Timeout .* required`)
@@ -3,9 +3,11 @@ module github.com/powerman/appcfg
 go 1.25.0
 
 require (
+	github.com/pelletier/go-toml/v2 v2.2.4
 	github.com/powerman/check v1.9.1
 	github.com/smartystreets/goconvey v1.8.1
 	github.com/spf13/pflag v1.0.10
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
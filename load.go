@@ -0,0 +1,87 @@
+package appcfg
+
+import (
+	"flag"
+
+	"github.com/spf13/pflag"
+)
+
+// Validator may be implemented by cfg (see ProvideStruct) to check
+// constraints which span several fields. It's called by LoadFlags and
+// LoadPFlags after all other checks.
+//
+// Returned error is handled like by WrapErr, so error which contains
+// ValueError or RequiredError for a cfg field will be returned as a
+// FieldError for that field.
+type Validator interface {
+	Validate() error
+}
+
+// ValueError describes an error related to some cfg field Value. It may be
+// returned by Validator to let LoadFlags and LoadPFlags report related
+// field name, flag name and tags.
+type ValueError struct {
+	Value Value
+	Err   error
+}
+
+// Error implements error interface.
+func (e *ValueError) Error() string { return e.Err.Error() }
+
+// Unwrap returns e.Err.
+func (e *ValueError) Unwrap() error { return e.Err }
+
+// LoadFlags updates cfg using given providers (see ProvideStruct), then
+// adds cfg fields to fs (see AddFlags) and parses args, then checks for
+// unset required fields (see Validate) and finally calls cfg.Validate if
+// cfg implements Validator. If fs is nil then flags will be skipped.
+//
+// Returns first failed step's error. Flag names in returned FieldError
+// are looked up in fs (or in FlagSet used to add cfg fields if fs is nil).
+func LoadFlags(cfg any, fs *flag.FlagSet, args []string, providers ...Provider) error {
+	err := ProvideStruct(cfg, providers...)
+	if err != nil {
+		return err
+	}
+	names := flagName
+	if fs != nil {
+		AddFlags(fs, cfg)
+		err = fs.Parse(args)
+		if err != nil {
+			return err
+		}
+		names = flagNameIn(fs)
+	}
+	return validate(cfg, names)
+}
+
+// LoadPFlags is like LoadFlags but for [pflag.FlagSet] (see AddPFlags).
+func LoadPFlags(cfg any, fs *pflag.FlagSet, args []string, providers ...Provider) error {
+	err := ProvideStruct(cfg, providers...)
+	if err != nil {
+		return err
+	}
+	names := flagName
+	if fs != nil {
+		AddPFlags(fs, cfg)
+		err = fs.Parse(args)
+		if err != nil {
+			return err
+		}
+		names = pflagNameIn(fs)
+	}
+	return validate(cfg, names)
+}
+
+// validate checks cfg using Validate and Validator, reporting flag names
+// returned by flagName.
+func validate(cfg any, flagName func(Value) string) error {
+	err := validateRequired(cfg, flagName)
+	if err != nil {
+		return err
+	}
+	if v, ok := cfg.(Validator); ok {
+		return fieldError(v.Validate(), flagName, cfg)
+	}
+	return nil
+}
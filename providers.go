@@ -1,11 +1,18 @@
 package appcfg
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 )
 
+var (
+	errConflict   = errors.New("both are set")
+	errUnknownEnv = errors.New("unknown variable")
+)
+
 // Tags provide access to tags attached to some Value.
 type Tags interface {
 	// Get returns the value associated with key. If there is no such
@@ -23,17 +30,28 @@ type Provider interface {
 	Provide(value Value, name string, tags Tags) (provided bool, err error)
 }
 
+// envNamer is implemented by providers which use env tag.
+type envNamer interface {
+	envName(tags Tags) string
+}
+
 // FromEnv implements Provider using value from environment variable with
 // name defined by tag "env" with optional prefix.
 type FromEnv struct {
 	prefix    string
 	trimSpace bool
+	file      bool
+	lookupEnv func(string) (string, bool)
+	names     func() []string // Names of all variables.
+	source    string          // File name if lookupEnv does not use environment.
 }
 
 // NewFromEnv creates new FromEnv with optional prefix.
 func NewFromEnv(prefix string, opts ...FromEnvOption) *FromEnv {
 	f := &FromEnv{
-		prefix: prefix,
+		prefix:    prefix,
+		lookupEnv: os.LookupEnv,
+		names:     environNames,
 	}
 	for _, opt := range opts {
 		opt(f)
@@ -41,6 +59,22 @@ func NewFromEnv(prefix string, opts ...FromEnvOption) *FromEnv {
 	return f
 }
 
+func environNames() []string {
+	env := os.Environ()
+	names := make([]string, len(env))
+	for i, kv := range env {
+		names[i], _, _ = strings.Cut(kv, "=")
+	}
+	return names
+}
+
+func (f *FromEnv) envName(tags Tags) string {
+	if name := tags.Get("env"); name != "" {
+		return "$" + f.prefix + name
+	}
+	return ""
+}
+
 // Provide implements Provider.
 func (f *FromEnv) Provide(value Value, _ string, tags Tags) (bool, error) {
 	name := tags.Get("env")
@@ -48,19 +82,131 @@ func (f *FromEnv) Provide(value Value, _ string, tags Tags) (bool, error) {
 		return false, nil
 	}
 	name = f.prefix + name
-	if s, ok := os.LookupEnv(name); ok {
+	s, ok := f.lookupEnv(name)
+	if f.file {
+		path, _ := f.lookupEnv(name + "_FILE")
+		switch {
+		case path != "" && ok:
+			return false, fmt.Errorf("$%s and $%s_FILE: %w", name, name, errConflict)
+		case path != "":
+			return f.provideFile(value, name+"_FILE", path)
+		}
+	}
+	if ok {
 		if f.trimSpace {
 			s = strings.TrimSpace(s)
 		}
 		err := value.Set(s)
 		if err != nil {
-			err = fmt.Errorf("$%s=%q: %w", name, s, err)
+			return true, fmt.Errorf("$%s=%s: %w", name, quote(value, s), newRawError(value, s, err))
+		}
+		if f.source != "" {
+			setSource(value, SourceFile, f.source+":$"+name, s)
+		} else {
+			setSource(value, SourceEnv, "$"+name, s)
 		}
-		return true, err
+		return true, nil
 	}
 	return false, nil
 }
 
+func (f *FromEnv) provideFile(value Value, name, path string) (bool, error) {
+	buf, err := os.ReadFile(path) //nolint:gosec // File inclusion is a feature.
+	if err != nil {
+		return false, fmt.Errorf("$%s=%q: %w", name, path, err)
+	}
+	s := strings.TrimRight(string(buf), "\n")
+	if f.trimSpace {
+		s = strings.TrimSpace(s)
+	}
+	err = value.Set(s)
+	if err != nil {
+		return true, fmt.Errorf("$%s=%q: %s: %w", name, path, quote(value, s), newRawError(value, s, err))
+	}
+	setSource(value, SourceFile, path, s)
+	return true, nil
+}
+
+// CheckUnknown returns error for each environment variable with prefix
+// which is not used by env tag of any field in cfgs (see ProvideStruct),
+// e.g. because of a typo in variable name. If there is a known variable
+// with similar name then error will include it as a suggestion.
+//
+// All such errors (one per variable, sorted by name) are returned joined
+// using [errors.Join]. Returns nil if prefix is empty.
+func (f *FromEnv) CheckUnknown(cfgs ...any) error {
+	if f.prefix == "" {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, cfg := range cfgs {
+		forStruct(cfg, func(_ Value, _ string, tags Tags) {
+			if name := tags.Get("env"); name != "" {
+				known[f.prefix+name] = true
+				if f.file {
+					known[f.prefix+name+"_FILE"] = true
+				}
+			}
+		})
+	}
+	var names []string
+	for _, name := range f.names() {
+		if strings.HasPrefix(name, f.prefix) && !known[name] {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	source := ""
+	if f.source != "" {
+		source = f.source + ":"
+	}
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		if similar := similarName(name, known); similar != "" {
+			errs = append(errs, fmt.Errorf("%s$%s: %w (did you mean $%s?)", source, name, errUnknownEnv, similar))
+		} else {
+			errs = append(errs, fmt.Errorf("%s$%s: %w", source, name, errUnknownEnv))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// similarName returns name from known which is most similar to given name
+// or empty string if there are no similar names.
+func similarName(name string, known map[string]bool) (similar string) {
+	maxDist := max(1, len(name)/5)
+	for k := range known {
+		dist := editDistance(name, k)
+		if dist < maxDist || (dist == maxDist && (similar == "" || k < similar)) {
+			similar, maxDist = k, dist
+		}
+	}
+	return similar
+}
+
+// editDistance returns Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range ra {
+		cur[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
 // FromEnvOption is an option for NewFromEnv.
 type FromEnvOption func(*FromEnv)
 
@@ -69,3 +215,12 @@ type FromEnvOption func(*FromEnv)
 func FromEnvTrimSpace() FromEnvOption {
 	return func(f *FromEnv) { f.trimSpace = true }
 }
+
+// FromEnvFile adds support for common convention to provide value in a
+// file: if environment variable NAME is not set but NAME_FILE is set
+// (and not empty) then content of file with path in NAME_FILE will be
+// used as a value, without trailing newlines (like shell $(<file) does).
+// It's an error if both variables are set.
+func FromEnvFile() FromEnvOption {
+	return func(f *FromEnv) { f.file = true }
+}
@@ -0,0 +1,27 @@
+package appcfg
+
+import "fmt"
+
+// FromDefault implements Provider using default value defined by tag
+// "default". It sets value only if it is still unset, so it should be
+// used as a last provider to ProvideStruct.
+type FromDefault struct{}
+
+// NewFromDefault creates new FromDefault.
+func NewFromDefault() *FromDefault {
+	return &FromDefault{}
+}
+
+// Provide implements Provider.
+func (*FromDefault) Provide(value Value, _ string, tags Tags) (bool, error) {
+	s, ok := tags.Lookup("default")
+	if !ok || value.Get() != nil {
+		return false, nil
+	}
+	err := value.Set(s)
+	if err != nil {
+		return true, fmt.Errorf("default:%s: %w", quote(value, s), newRawError(value, s, err))
+	}
+	setSource(value, SourceDefault, "", s)
+	return true, nil
+}
@@ -0,0 +1,133 @@
+package appcfg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	errInvalidFileName = errors.New("invalid file name")
+	errFileTooLarge    = errors.New("file too large")
+)
+
+// FromDir implements Provider using content of file in a directory with
+// name defined by tag "file". It's suitable for Docker secrets (like
+// /run/secrets/db_password) and Kubernetes Secret and ConfigMap volumes.
+//
+// Missing file (or directory) is handled as absent value.
+//
+// If directory contains "..data" symlink (Kubernetes volume layout) then
+// it'll be resolved before reading each file, to avoid reading file from
+// another version of volume while it's being updated.
+type FromDir struct {
+	dir         string
+	envTag      bool
+	envToName   func(string) string
+	trimNewline bool
+	maxSize     int64
+}
+
+// NewFromDir creates new FromDir for given directory.
+func NewFromDir(dir string, opts ...FromDirOption) *FromDir {
+	f := &FromDir{
+		dir: dir,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Provide implements Provider.
+func (f *FromDir) Provide(value Value, _ string, tags Tags) (bool, error) {
+	name := tags.Get("file")
+	if name == "" && f.envTag {
+		name = tags.Get("env")
+		if name != "" && f.envToName != nil {
+			name = f.envToName(name)
+		}
+	}
+	if name == "" {
+		return false, nil
+	}
+	if !filepath.IsLocal(name) {
+		return false, fmt.Errorf("%q: %w", name, errInvalidFileName)
+	}
+
+	dir := f.dir
+	if data, err := filepath.EvalSymlinks(filepath.Join(dir, "..data")); err == nil {
+		dir = data
+	}
+	path := filepath.Join(dir, name)
+	s, err := f.read(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	path = filepath.Join(f.dir, name)
+	err = value.Set(s)
+	if err != nil {
+		return true, fmt.Errorf("%s=%s: %w", path, quote(value, s), newRawError(value, s, err))
+	}
+	setSource(value, SourceFile, path, s)
+	return true, nil
+}
+
+func (f *FromDir) read(path string) (string, error) {
+	file, err := os.Open(path) //nolint:gosec // File inclusion is a feature.
+	if err != nil {
+		return "", err
+	}
+	defer file.Close() //nolint:errcheck // Read-only file.
+
+	r := io.Reader(file)
+	if f.maxSize > 0 {
+		r = io.LimitReader(file, f.maxSize+1)
+	}
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	if f.maxSize > 0 && int64(len(buf)) > f.maxSize {
+		return "", fmt.Errorf("%s: %w (more than %d bytes)", path, errFileTooLarge, f.maxSize)
+	}
+
+	s := string(buf)
+	if f.trimNewline && strings.HasSuffix(s, "\n") {
+		s = strings.TrimSuffix(s[:len(s)-1], "\r")
+	}
+	return s, nil
+}
+
+// FromDirOption is an option for NewFromDir.
+type FromDirOption func(*FromDir)
+
+// FromDirEnvTag makes FromDir use tag "env" as a file name for values
+// without tag "file". If toName is not nil it'll be used to convert value
+// of tag "env" into a file name (e.g. [strings.ToLower]).
+func FromDirEnvTag(toName func(string) string) FromDirOption {
+	return func(f *FromDir) {
+		f.envTag = true
+		f.envToName = toName
+	}
+}
+
+// FromDirTrimNewline removes single trailing newline ("\n" or "\r\n")
+// from file content.
+func FromDirTrimNewline() FromDirOption {
+	return func(f *FromDir) { f.trimNewline = true }
+}
+
+// FromDirMaxSize limits allowed file size, returning an error for larger
+// files.
+func FromDirMaxSize(size int64) FromDirOption {
+	return func(f *FromDir) { f.maxSize = size }
+}
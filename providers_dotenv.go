@@ -0,0 +1,206 @@
+package appcfg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var (
+	errInvalidKey      = errors.New("invalid variable name")
+	errNoAssign        = errors.New("expected '=' after variable name")
+	errUnterminated    = errors.New("unterminated quoted value")
+	errUnexpectedChars = errors.New("unexpected characters after quoted value")
+)
+
+// FromDotenv implements Provider using value from .env file variable with
+// name defined by tag "env" with optional prefix - it works exactly like
+// FromEnv, but does not use (or modify) environment variables.
+//
+// File format is compatible with shell and most .env implementations:
+//
+//	# Comment.
+//	KEY=unquoted value # Comment.
+//	export KEY=value
+//	KEY='single quoted value, no escapes, may be multi-line'
+//	KEY="double quoted value, supports \" \\ \$ \n \r \t escapes,
+//	may be multi-line"
+//
+// Variables are not expanded.
+type FromDotenv struct {
+	name string
+	vars map[string]string
+	env  *FromEnv
+}
+
+// NewFromDotenv creates new FromDotenv using variables read from r, with
+// optional prefix.
+func NewFromDotenv(r io.Reader, prefix string, opts ...FromEnvOption) (*FromDotenv, error) {
+	f := &FromDotenv{name: readerName(r, ".env")}
+	buf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.name, err)
+	}
+	f.vars, err = parseDotenv(string(buf))
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", f.name, err)
+	}
+	f.env = NewFromEnv(prefix, opts...)
+	f.env.lookupEnv = f.Lookup
+	f.env.names = func() []string { return slices.Collect(maps.Keys(f.vars)) }
+	f.env.source = f.name
+	return f, nil
+}
+
+// NewFromDotenvFile creates new FromDotenv using variables read from file,
+// with optional prefix.
+func NewFromDotenvFile(name, prefix string, opts ...FromEnvOption) (*FromDotenv, error) {
+	return readFile(name, func(r io.Reader) (*FromDotenv, error) {
+		return NewFromDotenv(r, prefix, opts...)
+	})
+}
+
+// Lookup returns value of variable (with prefix) defined in .env file.
+func (f *FromDotenv) Lookup(name string) (string, bool) {
+	s, ok := f.vars[name]
+	return s, ok
+}
+
+// CheckUnknown works like FromEnv.CheckUnknown for variables defined in
+// .env file.
+func (f *FromDotenv) CheckUnknown(cfgs ...any) error { return f.env.CheckUnknown(cfgs...) }
+
+func (f *FromDotenv) envName(tags Tags) string { return f.env.envName(tags) }
+
+// Provide implements Provider.
+func (f *FromDotenv) Provide(value Value, name string, tags Tags) (bool, error) {
+	ok, err := f.env.Provide(value, name, tags)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", f.name, err)
+	}
+	return ok, err
+}
+
+// parseDotenv returns variables defined in .env file content s.
+// Returned error starts with line number.
+func parseDotenv(s string) (map[string]string, error) {
+	vars := make(map[string]string)
+	line := 1
+	for s != "" {
+		switch {
+		case s[0] == '\n':
+			line++
+			s = s[1:]
+			continue
+		case s[0] == ' ' || s[0] == '\t' || s[0] == '\r':
+			s = s[1:]
+			continue
+		case s[0] == '#':
+			s = skipLine(s)
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(s, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			s = strings.TrimLeft(rest, " \t")
+		}
+		i := strings.IndexFunc(s, func(r rune) bool { return !isDotenvKey(r) })
+		if i == -1 {
+			i = len(s)
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("%d: %w", line, errInvalidKey)
+		}
+		key := s[:i]
+		s = strings.TrimLeft(s[i:], " \t")
+		if s == "" || s[0] != '=' {
+			return nil, fmt.Errorf("%d: %w", line, errNoAssign)
+		}
+		s = s[1:]
+		if rest := strings.TrimLeft(s, " \t"); rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+			s = rest
+		}
+
+		var value string
+		var err error
+		switch {
+		case s != "" && (s[0] == '\'' || s[0] == '"'):
+			var end int
+			value, end, err = unquote(s)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", line, err)
+			}
+			line += strings.Count(s[:end], "\n")
+			rest := strings.TrimLeft(s[end+1:], " \t\r")
+			if rest != "" && rest[0] != '\n' && rest[0] != '#' {
+				return nil, fmt.Errorf("%d: %w", line, errUnexpectedChars)
+			}
+			s = skipLine(rest)
+		default:
+			eol := strings.IndexByte(s, '\n')
+			if eol == -1 {
+				eol = len(s)
+			}
+			value, _, _ = strings.Cut(s[:eol], " #")
+			value, _, _ = strings.Cut(value, "\t#")
+			value = strings.TrimSpace(value)
+			s = s[eol:]
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+func isDotenvKey(r rune) bool {
+	return r == '_' || r == '.' || r == '-' ||
+		'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+}
+
+// skipLine returns s starting with first '\n' or empty.
+func skipLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i != -1 {
+		return s[i:]
+	}
+	return ""
+}
+
+// unquote returns unquoted value of quoted string at the beginning of s
+// and index of closing quote in s.
+func unquote(s string) (value string, end int, err error) {
+	quote := s[0]
+	if quote == '\'' {
+		end = strings.IndexByte(s[1:], quote) + 1
+		if end == 0 {
+			return "", 0, errUnterminated
+		}
+		return s[1:end], end, nil
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == quote:
+			return b.String(), i, nil
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$', '`':
+				b.WriteByte(s[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, errUnterminated
+}
@@ -0,0 +1,88 @@
+package appcfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNotObject    = errors.New("not an object")
+	errTrailingData = errors.New("unexpected data after top-level value")
+)
+
+// FromJSON implements Provider using value from JSON document with path
+// defined by tag "json" (see Config files in package docs).
+//
+// JSON null is handled as absent value. JSON numbers are set using their
+// text, without conversion to float64.
+type FromJSON struct {
+	name string
+	doc  jsonDoc
+}
+
+// NewFromJSON creates new FromJSON using JSON object read from r.
+// It's an error if r contains anything except whitespace after the object.
+func NewFromJSON(r io.Reader) (*FromJSON, error) {
+	f := &FromJSON{name: readerName(r, "JSON")}
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var doc any
+	err := dec.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.name, err)
+	}
+	if f.doc, _ = doc.(map[string]any); f.doc == nil {
+		return nil, fmt.Errorf("%s: %w", f.name, errNotObject)
+	}
+	if _, err = dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", f.name, errTrailingData)
+	}
+	return f, nil
+}
+
+// NewFromJSONFile creates new FromJSON using JSON object read from file.
+func NewFromJSONFile(name string) (*FromJSON, error) {
+	return readFile(name, NewFromJSON)
+}
+
+// Provide implements Provider.
+func (f *FromJSON) Provide(value Value, _ string, tags Tags) (bool, error) {
+	return provideDoc(f.doc, f.name, "json", value, tags)
+}
+
+// jsonDoc implements document for decoded JSON object.
+type jsonDoc map[string]any
+
+func (d jsonDoc) lookup(path string) (any, bool) {
+	v, ok := lookupPath(d, path)
+	return v, ok && v != nil
+}
+
+func (jsonDoc) elems(v any) ([]any, bool) {
+	elems, ok := v.([]any)
+	return elems, ok
+}
+
+// text returns string for JSON scalars or JSON text for other values.
+func (jsonDoc) text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v) // Can't fail on decoded JSON.
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func (jsonDoc) pos(any) string { return "" }
@@ -0,0 +1,440 @@
+package appcfg_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/powerman/check"
+
+	"github.com/powerman/appcfg"
+)
+
+func TestFromDocument(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	tests := []struct {
+		ext     string
+		newFile func(name string) (appcfg.Provider, error)
+		doc     string
+		extra   string
+		posPort string
+		posBad  string
+	}{
+		{
+			ext:     "json",
+			newFile: func(name string) (appcfg.Provider, error) { return appcfg.NewFromJSONFile(name) },
+			doc: `{
+				"host": "localhost",
+				"port": 0,
+				"debug": true,
+				"timeout": null,
+				"db": {"ports": [80, 443], "hosts": [], "bad": [1, -1], "extra": {"a": "<b>"}}
+			}`,
+			extra: `{"a":"<b>"}`,
+		},
+		{
+			ext:     "yml",
+			newFile: func(name string) (appcfg.Provider, error) { return appcfg.NewFromYAMLFile(name) },
+			doc: `
+host: localhost
+port: 0
+debug: true
+timeout: ~
+db:
+  ports: [80, 443]
+  hosts: []
+  bad:
+    - 1
+    - -1
+  extra: {a: b}
+`,
+			extra:   `{a: b}`,
+			posPort: ":3:7",
+			posBad:  ":11:7",
+		},
+		{
+			ext:     "toml",
+			newFile: func(name string) (appcfg.Provider, error) { return appcfg.NewFromTOMLFile(name) },
+			doc: `
+host = "localhost"
+port = 0
+debug = true
+
+[db]
+ports = [80, 443]
+hosts = []
+bad = [1, -1]
+extra = {a = "b"}
+`,
+			extra: `a = 'b'`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ext, func(tt *testing.T) {
+			t := check.T(tt)
+			t.Parallel()
+
+			_, err := tc.newFile(filepath.Join(t.TempDir(), "none."+tc.ext))
+			t.True(os.IsNotExist(err))
+
+			name := filepath.Join(t.TempDir(), "config."+tc.ext)
+			t.Nil(os.WriteFile(name, []byte(tc.doc), 0o600))
+			provider, err := tc.newFile(name)
+			t.Nil(err)
+
+			var cfg struct {
+				Host    appcfg.String    `json:"host,omitempty" toml:"host,omitempty" yaml:"host,omitempty"`
+				Port    appcfg.Port      `json:"port"           toml:"port"           yaml:"port"`
+				Debug   appcfg.Bool      `json:"debug"          toml:"debug"          yaml:"debug"`
+				Timeout appcfg.Duration  `json:"timeout"        toml:"timeout"        yaml:"timeout"`
+				Missing appcfg.String    `json:"db.missing"     toml:"db.missing"     yaml:"db.missing"`
+				Skip    appcfg.String    `json:"-"              toml:"-"              yaml:"-"`
+				Ports   appcfg.PortSlice `json:"db.ports"       toml:"db.ports"       yaml:"db.ports"`
+				DB      struct {
+					Hosts appcfg.EndpointSlice `json:"hosts"     toml:"hosts"     yaml:"hosts"`
+					Bad   appcfg.PortSlice     `json:"bad"       toml:"bad"       yaml:"bad"`
+					Extra appcfg.String        `json:"extra"     toml:"extra"     yaml:"extra"`
+					Deep  appcfg.String        `json:"extra.a.b" toml:"extra.a.b" yaml:"extra.a.b"`
+				} `jsonPrefix:"db." tomlPrefix:"db." yamlPrefix:"db."`
+			}
+			cfg.Timeout = appcfg.MustDuration("3s")
+			cfg.Ports = appcfg.MustPortSlice("8080")
+
+			err = appcfg.ProvideStruct(&cfg, provider)
+			t.Match(err, `^Port \(.*\): .*config.`+tc.ext+tc.posPort+`: port="0": not between`)
+			t.Match(err, `(?m)^DB.Bad \(.*\): .*config.`+tc.ext+tc.posBad+`: db.bad\[1\]="-1": not between`)
+			t.Len(appcfg.FieldErrors(err), 2)
+			t.Equal(cfg.Host.String(), "localhost")
+			t.Equal(cfg.Debug.String(), "true")
+			t.Equal(cfg.Timeout.String(), "3s")
+			t.Nil(cfg.Missing.Get())
+			t.Nil(cfg.Skip.Get())
+			t.Equal(cfg.Ports.String(), "[80 443]")
+			t.DeepEqual(cfg.DB.Hosts.Get(), []string{})
+			t.Equal(cfg.DB.Extra.String(), tc.extra)
+			t.Nil(cfg.DB.Deep.Get())
+		})
+	}
+}
+
+func TestFromJSON(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	_, err := appcfg.NewFromJSON(strings.NewReader(`[]`))
+	t.Match(err, `^JSON: not an object`)
+	_, err = appcfg.NewFromJSON(strings.NewReader(`{`))
+	t.Match(err, `^JSON: unexpected EOF`)
+	_, err = appcfg.NewFromJSON(strings.NewReader(`{"a":1} garbage`))
+	t.Match(err, `^JSON: unexpected data after top-level value`)
+	_, err = appcfg.NewFromJSON(strings.NewReader(`{} {}`))
+	t.Match(err, `^JSON: unexpected data after top-level value`)
+	_, err = appcfg.NewFromJSON(strings.NewReader("{}\n\t \n"))
+	t.Nil(err)
+
+	fromJSON, err := appcfg.NewFromJSON(strings.NewReader(`{"big": 1e400, "int": 12345678901234567890}`))
+	t.Nil(err)
+	var cfg struct {
+		Big appcfg.String `json:"big"`
+		Int appcfg.String `json:"int"`
+	}
+	t.Nil(appcfg.ProvideStruct(&cfg, fromJSON))
+	t.Equal(cfg.Big.String(), "1e400")
+	t.Equal(cfg.Int.String(), "12345678901234567890")
+}
+
+func TestFromYAML(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	_, err := appcfg.NewFromYAML(strings.NewReader(`- a`))
+	t.Match(err, `^YAML:1:1: not a mapping`)
+	_, err = appcfg.NewFromYAML(strings.NewReader(`a: [`))
+	t.Match(err, `^YAML: yaml:`)
+	fromYAML, err := appcfg.NewFromYAML(strings.NewReader(``))
+	t.Nil(err)
+	var empty struct {
+		Host appcfg.String `yaml:"host"`
+	}
+	t.Nil(appcfg.ProvideStruct(&empty, fromYAML))
+	t.Nil(empty.Host.Get())
+
+	fromYAML, err = appcfg.NewFromYAML(strings.NewReader(`
+host: &host localhost
+db: &db
+  host: *host
+  port: x
+replica: *db
+`))
+	t.Nil(err)
+	var cfg struct {
+		Host        appcfg.String `yaml:"host"`
+		DBHost      appcfg.String `yaml:"db.host"`
+		ReplicaHost appcfg.String `yaml:"replica.host"`
+		ReplicaPort appcfg.Port   `yaml:"replica.port"`
+	}
+	err = appcfg.ProvideStruct(&cfg, fromYAML)
+	t.Match(err, `^ReplicaPort \(yaml:"replica.port"\): YAML:5:9: replica.port="x": .*invalid syntax`)
+	t.Equal(cfg.Host.String(), "localhost")
+	t.Equal(cfg.DBHost.String(), "localhost")
+	t.Equal(cfg.ReplicaHost.String(), "localhost")
+}
+
+func TestFromTOML(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	_, err := appcfg.NewFromTOML(strings.NewReader("a = 1\nb = ["))
+	t.Match(err, `^TOML:2:6: toml: expected`)
+
+	fromTOML, err := appcfg.NewFromTOML(strings.NewReader(`
+ratio = 0.5
+since = 2024-01-02T03:04:05Z
+local = 2024-01-02T03:04:05
+day = 2024-01-02
+at = 03:04:05
+timeouts = ["1s", "1m"]
+`))
+	t.Nil(err)
+	var cfg struct {
+		Ratio    appcfg.Float64       `toml:"ratio"`
+		Since    appcfg.String        `toml:"since"`
+		Local    appcfg.String        `toml:"local"`
+		Day      appcfg.String        `toml:"day"`
+		At       appcfg.String        `toml:"at"`
+		Timeouts appcfg.DurationSlice `toml:"timeouts"`
+	}
+	t.Nil(appcfg.ProvideStruct(&cfg, fromTOML))
+	t.Equal(cfg.Ratio.String(), "0.5")
+	t.Equal(cfg.Since.String(), "2024-01-02T03:04:05Z")
+	t.Equal(cfg.Local.String(), "2024-01-02T03:04:05")
+	t.Equal(cfg.Day.String(), "2024-01-02")
+	t.Equal(cfg.At.String(), "03:04:05")
+	t.Equal(cfg.Timeouts.String(), "[1s 1m0s]")
+}
+
+func TestFromDotenv(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	tests := []struct {
+		dotenv string
+		want   string
+	}{
+		{"A", `^.env:1: expected '='`},
+		{"\n\n=1", `^.env:3: invalid variable name`},
+		{"A='1", `^.env:1: unterminated`},
+		{"A=\"1\\\"", `^.env:1: unterminated`},
+		{"A=\"1\n2\" 3", `^.env:2: unexpected characters`},
+	}
+	for _, tc := range tests {
+		t.Run("", func(tt *testing.T) {
+			t := check.T(tt)
+			_, err := appcfg.NewFromDotenv(strings.NewReader(tc.dotenv), "")
+			t.Match(err, tc.want)
+		})
+	}
+	_, err := appcfg.NewFromDotenvFile(filepath.Join(t.TempDir(), "none.env"), "")
+	t.True(os.IsNotExist(err))
+
+	name := filepath.Join(t.TempDir(), "test.env")
+	t.Nil(os.WriteFile(name, []byte(`# Comment.
+APP_HOST = localhost # Comment.
+export APP_PORT=0
+	APP_EMPTY=
+APP_SINGLE='a \n "b"
+c' # Comment.
+APP_DOUBLE="a \n \"b\" \$c \x
+d"
+APP_HASH=a#b
+APP_COMMENT= # Comment.
+APP_TAB=	#b
+APP_SPACE= " a "   
+`), 0o600))
+	fromDotenv, err := appcfg.NewFromDotenvFile(name, "APP_", appcfg.FromEnvTrimSpace())
+	t.Nil(err)
+
+	var cfg struct {
+		Host    appcfg.String `env:"HOST"`
+		Port    appcfg.Port   `env:"PORT"`
+		Empty   appcfg.String `env:"EMPTY"`
+		Single  appcfg.String `env:"SINGLE"`
+		Double  appcfg.String `env:"DOUBLE"`
+		Hash    appcfg.String `env:"HASH"`
+		Comment appcfg.String `env:"COMMENT"`
+		Tab     appcfg.String `env:"TAB"`
+		Space   appcfg.String `env:"SPACE"`
+		Path    appcfg.String `env:"PATH"`
+	}
+	err = appcfg.ProvideStruct(&cfg, fromDotenv)
+	t.Match(err, `^Port \(env:"PORT"\): .*test.env: \$APP_PORT="0": not between`)
+	t.Equal(cfg.Host.String(), "localhost")
+	t.Equal(cfg.Empty.Get(), "")
+	t.Equal(cfg.Single.String(), "a \\n \"b\"\nc")
+	t.Equal(cfg.Double.String(), "a \n \"b\" $c \\x\nd")
+	t.Equal(cfg.Hash.String(), "a#b")
+	t.Equal(cfg.Comment.Get(), "")
+	t.Equal(cfg.Tab.Get(), "")
+	t.Equal(cfg.Space.String(), "a")
+	t.Nil(cfg.Path.Get())
+	_, ok := fromDotenv.Lookup("APP_HOST")
+	t.True(ok)
+	_, ok = fromDotenv.Lookup("PATH")
+	t.False(ok)
+}
+
+func TestFromDir(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	dir := t.TempDir()
+	t.Nil(os.Mkdir(filepath.Join(dir, "..2024_01_02"), 0o700))
+	for name, content := range map[string]string{
+		"host":        "localhost\n",
+		"port":        "0",
+		"db_password": "secret\r\n",
+		"big":         "12345678901",
+	} {
+		t.Nil(os.WriteFile(filepath.Join(dir, "..2024_01_02", name), []byte(content), 0o600))
+		t.Nil(os.Symlink(filepath.Join("..data", name), filepath.Join(dir, name)))
+	}
+	t.Nil(os.Symlink("..2024_01_02", filepath.Join(dir, "..data")))
+
+	var cfg struct {
+		Host       appcfg.String `file:"host"`
+		Port       appcfg.Port   `file:"port"`
+		DBPassword appcfg.String `env:"DB_PASSWORD"`
+		Big        appcfg.String `file:"big"`
+		Missing    appcfg.String `file:"missing"`
+		Bad        appcfg.String `file:"../host"`
+		NoTag      appcfg.String
+	}
+	fromDir := appcfg.NewFromDir(dir,
+		appcfg.FromDirEnvTag(strings.ToLower),
+		appcfg.FromDirTrimNewline(),
+		appcfg.FromDirMaxSize(10),
+	)
+	err := appcfg.ProvideStruct(&cfg, fromDir)
+	t.Match(err, `(?m)^Port \(file:"port"\): .*/port="0": not between`)
+	t.Match(err, `(?m)^Big \(file:"big"\): .*/big: file too large`)
+	t.Match(err, `(?m)^Bad \(file:"../host"\): "../host": invalid file name`)
+	t.Len(appcfg.FieldErrors(err), 3)
+	t.Equal(cfg.Host.String(), "localhost")
+	t.Equal(cfg.DBPassword.String(), "secret")
+	t.Nil(cfg.Missing.Get())
+	t.Nil(cfg.NoTag.Get())
+
+	var cfg2 struct {
+		Host appcfg.String `file:"host"`
+	}
+	t.Nil(appcfg.ProvideStruct(&cfg2, appcfg.NewFromDir(filepath.Join(dir, "none"))))
+	t.Nil(cfg2.Host.Get())
+	t.Nil(appcfg.ProvideStruct(&cfg2, appcfg.NewFromDir(dir)))
+	t.Equal(cfg2.Host.String(), "localhost\n")
+}
+
+func TestFromDefault(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Host    appcfg.String   `key:"host" default:"localhost"`
+		Port    appcfg.Port     `key:"port" default:"80"`
+		Timeout appcfg.Duration `default:"30s"`
+		Ports   appcfg.IntSlice `default:""`
+		Retries appcfg.Int      `default:"3"`
+		Bad     appcfg.Duration `default:"30"`
+		NoTag   appcfg.String
+	}
+	cfg.Retries = appcfg.MustInt("5")
+	err := appcfg.ProvideStruct(&cfg, fromMap{"port": "443"}, appcfg.NewFromDefault())
+	t.Match(err, `^Bad \(default:"30"\): default:"30": time: missing unit`)
+	t.Len(appcfg.FieldErrors(err), 1)
+	t.Equal(cfg.Host.String(), "localhost")
+	t.Equal(cfg.Port.String(), "443")
+	t.Equal(cfg.Timeout.String(), "30s")
+	t.DeepEqual(cfg.Ports.Get(), []int{})
+	t.Equal(cfg.Retries.String(), "5")
+	t.Nil(cfg.NoTag.Get())
+	t.Equal(appcfg.SourceOf(&cfg.Timeout), appcfg.Source{Kind: appcfg.SourceDefault, Raw: "30s"})
+}
+
+func TestFromEnvFile(tt *testing.T) {
+	t := check.T(tt)
+
+	dir := t.TempDir()
+	t.Nil(os.WriteFile(filepath.Join(dir, "host"), []byte(" localhost \n\n"), 0o600))
+	t.Nil(os.WriteFile(filepath.Join(dir, "port"), []byte("0\n"), 0o600))
+	t.Setenv("APP_HOST_FILE", filepath.Join(dir, "host"))
+	t.Setenv("APP_PORT_FILE", filepath.Join(dir, "port"))
+	t.Setenv("APP_USER", "root")
+	t.Setenv("APP_USER_FILE", filepath.Join(dir, "user"))
+	t.Setenv("APP_PASS_FILE", filepath.Join(dir, "pass"))
+	t.Setenv("APP_EMPTY", "empty")
+	t.Setenv("APP_EMPTY_FILE", "")
+
+	var cfg struct {
+		Host  appcfg.String `env:"HOST"`
+		Port  appcfg.Port   `env:"PORT"`
+		User  appcfg.String `env:"USER"`
+		Pass  appcfg.String `env:"PASS"`
+		Empty appcfg.String `env:"EMPTY"`
+	}
+	err := appcfg.ProvideStruct(&cfg, appcfg.NewFromEnv("APP_"))
+	t.Nil(err)
+	t.Nil(cfg.Host.Get())
+
+	err = appcfg.ProvideStruct(&cfg, appcfg.NewFromEnv("APP_", appcfg.FromEnvFile()))
+	t.Match(err, `(?m)^Port \(env:"PORT"\): \$APP_PORT_FILE=".*/port": "0": not between`)
+	t.Match(err, `(?m)^User \(env:"USER"\): \$APP_USER and \$APP_USER_FILE: both are set`)
+	t.Match(err, `(?m)^Pass \(env:"PASS"\): \$APP_PASS_FILE=".*/pass": open .*: no such file`)
+	t.Len(appcfg.FieldErrors(err), 3)
+	t.Equal(appcfg.FieldErrors(err)[0].Env, "$APP_PORT")
+	t.Equal(cfg.Host.Get(), " localhost ")
+	t.Equal(cfg.Empty.Get(), "empty")
+
+	err = appcfg.ProvideStruct(&cfg, appcfg.NewFromEnv("APP_", appcfg.FromEnvFile(), appcfg.FromEnvTrimSpace()))
+	t.Len(appcfg.FieldErrors(err), 3)
+	t.Equal(cfg.Host.Get(), "localhost")
+
+	t.Nil(appcfg.NewFromEnv("APP_", appcfg.FromEnvFile()).CheckUnknown(&cfg))
+	t.Match(appcfg.NewFromEnv("APP_").CheckUnknown(&cfg), `(?m)^\$APP_HOST_FILE: unknown variable$`)
+}
+
+func TestCheckUnknown(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var cfg struct {
+		Host    appcfg.String `env:"HOST"`
+		Retries appcfg.Int    `env:"RETRIES"`
+		DB      struct {
+			Password appcfg.String `env:"PASSWORD"`
+		} `envPrefix:"DB_"`
+	}
+	fromDotenv, err := appcfg.NewFromDotenv(strings.NewReader(`
+EXAMPLE_HOST=localhost
+EXAMPLE_RETIRES=2
+EXAMPLE_DB_PASSWORD_FILE=/run/secrets/db
+EXAMPLE_TIMEOUT=30s
+OTHER_VAR=1
+`), "EXAMPLE_")
+	t.Nil(err)
+	t.Nil(appcfg.ProvideStruct(&cfg, fromDotenv))
+	err = fromDotenv.CheckUnknown(&cfg)
+	t.Equal(err.Error(), `.env:$EXAMPLE_DB_PASSWORD_FILE: unknown variable
+.env:$EXAMPLE_RETIRES: unknown variable (did you mean $EXAMPLE_RETRIES?)
+.env:$EXAMPLE_TIMEOUT: unknown variable`)
+
+	fromDotenv, err = appcfg.NewFromDotenv(strings.NewReader("EXAMPLE_DB_PASSWORD_FILE=/run/secrets/db"),
+		"EXAMPLE_", appcfg.FromEnvFile())
+	t.Nil(err)
+	t.Nil(fromDotenv.CheckUnknown(&cfg))
+
+	fromDotenv, err = appcfg.NewFromDotenv(strings.NewReader("EXAMPLE_TIMEOUT=30s"), "")
+	t.Nil(err)
+	t.Nil(fromDotenv.CheckUnknown(&cfg))
+}
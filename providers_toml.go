@@ -0,0 +1,81 @@
+package appcfg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// FromTOML implements Provider using value from TOML document with path
+// (like "section.key") defined by tag "toml" (see Config files in package
+// docs).
+//
+// Offset date-times are set in [time.RFC3339Nano] format, local
+// date-times, dates and times are set in RFC 3339 format without offset.
+type FromTOML struct {
+	name string
+	doc  tomlDoc
+}
+
+// NewFromTOML creates new FromTOML using TOML document read from r.
+func NewFromTOML(r io.Reader) (*FromTOML, error) {
+	f := &FromTOML{name: readerName(r, "TOML")}
+	err := toml.NewDecoder(r).Decode(&f.doc)
+	if decodeErr := new(toml.DecodeError); errors.As(err, &decodeErr) {
+		row, column := decodeErr.Position()
+		return nil, fmt.Errorf("%s:%d:%d: %w", f.name, row, column, err)
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.name, err)
+	}
+	return f, nil
+}
+
+// NewFromTOMLFile creates new FromTOML using TOML document read from file.
+func NewFromTOMLFile(name string) (*FromTOML, error) {
+	return readFile(name, NewFromTOML)
+}
+
+// Provide implements Provider.
+func (f *FromTOML) Provide(value Value, _ string, tags Tags) (bool, error) {
+	return provideDoc(f.doc, f.name, "toml", value, tags)
+}
+
+// tomlDoc implements document for decoded TOML document.
+type tomlDoc map[string]any
+
+func (d tomlDoc) lookup(path string) (any, bool) { return lookupPath(d, path) }
+
+func (tomlDoc) elems(v any) ([]any, bool) {
+	elems, ok := v.([]any)
+	return elems, ok
+}
+
+// text returns string for TOML scalars or TOML text for other values.
+func (tomlDoc) text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, parseBits)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case fmt.Stringer: // toml.LocalDate, toml.LocalTime, toml.LocalDateTime.
+		return v.String()
+	}
+	buf, err := toml.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(buf), "\n")
+}
+
+func (tomlDoc) pos(any) string { return "" }
@@ -0,0 +1,115 @@
+package appcfg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var errNotMapping = errors.New("not a mapping")
+
+// FromYAML implements Provider using value from YAML document with path
+// defined by tag "yaml" (see Config files in package docs).
+//
+// YAML null is handled as absent value, aliases are resolved. Errors
+// include position (line:column) of invalid value in YAML.
+type FromYAML struct {
+	name string
+	doc  yamlDoc
+}
+
+// NewFromYAML creates new FromYAML using first YAML document read from r.
+func NewFromYAML(r io.Reader) (*FromYAML, error) {
+	f := &FromYAML{name: readerName(r, "YAML")}
+	var doc yaml.Node
+	err := yaml.NewDecoder(r).Decode(&doc)
+	switch {
+	case errors.Is(err, io.EOF):
+		f.doc.root = &yaml.Node{Kind: yaml.MappingNode}
+	case err != nil:
+		return nil, fmt.Errorf("%s: %w", f.name, err)
+	case len(doc.Content) == 0 || resolveAlias(doc.Content[0]).Kind != yaml.MappingNode:
+		return nil, fmt.Errorf("%s:%d:%d: %w", f.name, doc.Line, doc.Column, errNotMapping)
+	default:
+		f.doc.root = resolveAlias(doc.Content[0])
+	}
+	return f, nil
+}
+
+// NewFromYAMLFile creates new FromYAML using first YAML document read
+// from file.
+func NewFromYAMLFile(name string) (*FromYAML, error) {
+	return readFile(name, NewFromYAML)
+}
+
+// Provide implements Provider.
+func (f *FromYAML) Provide(value Value, _ string, tags Tags) (bool, error) {
+	return provideDoc(f.doc, f.name, "yaml", value, tags)
+}
+
+// yamlDoc implements document for YAML mapping node.
+type yamlDoc struct {
+	root *yaml.Node
+}
+
+func (d yamlDoc) lookup(path string) (*yaml.Node, bool) {
+	node := lookupNode(d.root, path)
+	return node, node != nil && !(node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null")
+}
+
+func (yamlDoc) elems(node *yaml.Node) ([]*yaml.Node, bool) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, false
+	}
+	elems := make([]*yaml.Node, len(node.Content))
+	for i, elem := range node.Content {
+		elems[i] = resolveAlias(elem)
+	}
+	return elems, true
+}
+
+func (yamlDoc) text(node *yaml.Node) string { return yamlText(node) }
+
+func (yamlDoc) pos(node *yaml.Node) string { return fmt.Sprintf(":%d:%d", node.Line, node.Column) }
+
+// lookupNode returns node at dotted path in mapping node or nil.
+func lookupNode(node *yaml.Node, path string) *yaml.Node {
+	for key := range strings.SplitSeq(path, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var found *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				found = resolveAlias(node.Content[i+1])
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		node = found
+	}
+	return node
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// yamlText returns value for YAML scalars or YAML text for other nodes.
+func yamlText(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	buf, err := yaml.Marshal(node)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(buf), "\n")
+}
@@ -0,0 +1,227 @@
+package appcfg
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Reloader keeps current cfg (see ProvideStruct) and replaces it with a
+// new one on Reload if new one is valid.
+//
+// It's safe to call Get concurrently with Reload. Returned cfg must not be
+// modified (e.g. by calling Set), but it's safe to read it concurrently
+// (using String, Get or Value methods of cfg fields): it's an immutable
+// snapshot which won't be changed by Reload. Slices returned by Get and
+// Value methods are copies, so they may be modified by reader.
+type Reloader[T any] struct {
+	load      func() (*T, error)
+	cfg       atomic.Pointer[T]
+	mu        sync.Mutex
+	subs      []func(cfg *T, changed []string)
+	pending   []notification[T] // In order of replacing cfg.
+	notifying bool              // Some goroutine delivers pending notifications.
+}
+
+type notification[T any] struct {
+	cfg     *T
+	changed []string
+}
+
+// NewReloader creates new Reloader with cfg returned by load. Returns
+// error if load fails.
+//
+// Function load must return a new cfg each time it's called, usually by
+// creating a new cfg with defaults and calling LoadFlags or LoadPFlags
+// with a new FlagSet.
+func NewReloader[T any](load func() (*T, error)) (*Reloader[T], error) {
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+	complete(cfg)
+	r := &Reloader[T]{load: load}
+	r.cfg.Store(cfg)
+	return r, nil
+}
+
+// Get returns current cfg.
+func (r *Reloader[T]) Get() *T {
+	return r.cfg.Load()
+}
+
+// Subscribe adds fn to be called after successful Reload which has
+// changed values of some fields. Subscribers are called sequentially,
+// in order, after Reload has replaced current cfg - so fn may call Get,
+// Subscribe and Reload.
+//
+// Notifications about several Reloads are never delivered concurrently
+// and are delivered in same order as Reloads replaced cfg. So if Reload
+// is called while subscribers are notified about previous Reload (by
+// another goroutine or by fn) then its notification is delivered after
+// previous ones by the goroutine which delivers them, and Reload may
+// return before subscribers are notified.
+func (r *Reloader[T]) Subscribe(fn func(cfg *T, changed []string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs = append(r.subs, fn)
+}
+
+// Reload calls load to get a new cfg. If load fails then current cfg is
+// kept and error is returned. Otherwise new cfg replaces current one and
+// subscribers are notified with names of fields (like "DB.Host") which
+// values are changed (if any).
+func (r *Reloader[T]) Reload() (changed []string, err error) {
+	changed, err = r.replace()
+	if err != nil {
+		return nil, err
+	}
+	r.notify()
+	return changed, nil
+}
+
+// replace calls load and replaces current cfg with a new one. It returns
+// changed fields and adds notification about them to pending.
+func (r *Reloader[T]) replace() ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cfg, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	complete(cfg)
+	changed := changedFields(r.cfg.Load(), cfg)
+	r.cfg.Store(cfg)
+	if len(changed) > 0 {
+		r.pending = append(r.pending, notification[T]{cfg: cfg, changed: changed})
+	}
+	return changed, nil
+}
+
+// notify delivers pending notifications to subscribers, unless they are
+// already delivered by another call to notify.
+func (r *Reloader[T]) notify() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.notifying {
+		return
+	}
+	r.notifying = true
+	defer func() { r.notifying = false }()
+	for len(r.pending) > 0 {
+		n := r.pending[0]
+		r.pending = r.pending[1:]
+		r.deliver(slices.Clone(r.subs), n)
+	}
+}
+
+// deliver calls subs with n. It must be called with r.mu locked, it'll
+// be unlocked while subs are called.
+func (r *Reloader[T]) deliver(subs []func(cfg *T, changed []string), n notification[T]) {
+	r.mu.Unlock()
+	defer r.mu.Lock()
+	for _, fn := range subs {
+		fn(n.cfg, n.changed)
+	}
+}
+
+// WatchSignal calls Reload on each received signal until ctx is done.
+// If no signals are given then it'll watch for SIGHUP. Reload errors
+// are reported using onError (if not nil).
+func (r *Reloader[T]) WatchSignal(ctx context.Context, onError func(error), sig ...os.Signal) {
+	if len(sig) == 0 {
+		sig = []os.Signal{syscall.SIGHUP}
+	}
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sig...)
+	defer signal.Stop(c)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c:
+			r.reload(onError)
+		}
+	}
+}
+
+// WatchFiles calls Reload when modification time or size of any of given
+// files is changed (including file creation and removal), checking files
+// every interval until ctx is done. Reload errors are reported using
+// onError (if not nil).
+func (r *Reloader[T]) WatchFiles(ctx context.Context, interval time.Duration, onError func(error), names ...string) {
+	stat := func() []fileState {
+		states := make([]fileState, len(names))
+		for i, name := range names {
+			if fi, err := os.Stat(name); err == nil {
+				states[i] = fileState{modTime: fi.ModTime(), size: fi.Size()}
+			}
+		}
+		return states
+	}
+	states := stat()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if newStates := stat(); !sameFileStates(states, newStates) {
+				states = newStates
+				r.reload(onError)
+			}
+		}
+	}
+}
+
+func (r *Reloader[T]) reload(onError func(error)) {
+	_, err := r.Reload()
+	if err != nil && onError != nil {
+		onError(err)
+	}
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func sameFileStates(a, b []fileState) bool {
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
+
+// complete calls Get for all cfg fields to make sure Get won't modify
+// them later (see slice.Get), which makes cfg safe for concurrent reading.
+func complete(cfg any) {
+	forStruct(cfg, func(value Value, _ string, _ Tags) {
+		_ = value.Get()
+	})
+}
+
+// changedFields returns names of fields which has different values in
+// old and cur cfgs.
+func changedFields(old, cur any) []string {
+	values := make(map[string]any)
+	forStruct(old, func(value Value, name string, _ Tags) {
+		values[name] = value.Get()
+	})
+	var changed []string
+	forStruct(cur, func(value Value, name string, _ Tags) {
+		if !reflect.DeepEqual(values[name], value.Get()) {
+			changed = append(changed, name)
+		}
+	})
+	return changed
+}
@@ -0,0 +1,302 @@
+package appcfg_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/powerman/check"
+
+	"github.com/powerman/appcfg"
+)
+
+type reloadCfg struct {
+	LogLevel  appcfg.OneOfString `json:"log_level" default:"info"`
+	RateLimit appcfg.Int         `json:"rate_limit" default:"10"`
+	DB        struct {
+		Host appcfg.String `json:"host" default:"localhost"`
+	} `jsonPrefix:"db."`
+}
+
+func loadReloadCfg(name string) func() (*reloadCfg, error) {
+	return func() (*reloadCfg, error) {
+		cfg := &reloadCfg{
+			LogLevel: appcfg.NewOneOfString([]string{"debug", "info"}),
+		}
+		fromJSON, err := appcfg.NewFromJSONFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return cfg, appcfg.LoadFlags(cfg, nil, nil, fromJSON, appcfg.NewFromDefault())
+	}
+}
+
+func TestReloader(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "config.json")
+	_, err := appcfg.NewReloader(loadReloadCfg(name))
+	t.Match(err, "no such file|cannot find")
+
+	t.Nil(os.WriteFile(name, []byte(`{}`), 0o600))
+	r, err := appcfg.NewReloader(loadReloadCfg(name))
+	t.Nil(err)
+	cfg := r.Get()
+	t.Equal(cfg.LogLevel.String(), "info")
+
+	var notified [][]string
+	r.Subscribe(func(cfg *reloadCfg, changed []string) {
+		t.Equal(cfg, r.Get())
+		notified = append(notified, changed)
+	})
+
+	changed, err := r.Reload()
+	t.Nil(err)
+	t.Nil(changed)
+	t.Len(notified, 0)
+
+	t.Nil(os.WriteFile(name, []byte(`{"log_level":"debug","db":{"host":"db"}}`), 0o600))
+	changed, err = r.Reload()
+	t.Nil(err)
+	t.DeepEqual(changed, []string{"LogLevel", "DB.Host"})
+	t.DeepEqual(notified, [][]string{changed})
+	t.Equal(cfg.LogLevel.String(), "info")
+	t.Equal(r.Get().LogLevel.String(), "debug")
+
+	t.Nil(os.WriteFile(name, []byte(`{"log_level":"trace","rate_limit":5}`), 0o600))
+	changed, err = r.Reload()
+	t.Match(err, `^LogLevel .*"trace": not one of`)
+	t.Nil(changed)
+	t.Len(notified, 1)
+	t.Equal(r.Get().LogLevel.String(), "debug")
+	t.Equal(r.Get().RateLimit.String(), "10")
+
+	var nested []string
+	r.Subscribe(func(*reloadCfg, []string) {
+		r.Subscribe(func(*reloadCfg, []string) {})
+		changed, err := r.Reload()
+		t.Nil(err)
+		nested = changed
+	})
+	t.Nil(os.WriteFile(name, []byte(`{"rate_limit":5}`), 0o600))
+	changed, err = r.Reload()
+	t.Nil(err)
+	t.DeepEqual(changed, []string{"LogLevel", "RateLimit", "DB.Host"})
+	t.Len(notified, 2)
+	t.Nil(nested)
+}
+
+func TestReloaderWatchFiles(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "config.json")
+	t.Nil(os.WriteFile(name, []byte(`{}`), 0o600))
+	r, err := appcfg.NewReloader(loadReloadCfg(name))
+	t.Nil(err)
+	changes := make(chan []string, 1)
+	r.Subscribe(func(_ *reloadCfg, changed []string) { changes <- changed })
+	errs := make(chan error, 1)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go r.WatchFiles(ctx, time.Millisecond, func(err error) {
+		select {
+		case errs <- err: // Ignore errors while file is partially written.
+		default:
+		}
+	}, name)
+
+	// Keep changing file until WatchFiles will notice.
+	var changed []string
+	for rateLimit := "1"; changed == nil; rateLimit += "0" {
+		t.Nil(os.WriteFile(name, []byte(`{"rate_limit":`+rateLimit+`}`), 0o600))
+		select {
+		case changed = <-changes:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.DeepEqual(changed, []string{"RateLimit"})
+	t.NotEqual(r.Get().RateLimit.String(), "10")
+	rateLimit := r.Get().RateLimit.String()
+
+	t.Nil(os.Remove(name))
+	for err = <-errs; !errors.Is(err, os.ErrNotExist); { // Skip errors while file was written.
+		err = <-errs
+	}
+	t.Equal(r.Get().RateLimit.String(), rateLimit)
+}
+
+func TestReloaderWatchSignal(tt *testing.T) {
+	t := check.T(tt)
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGHUP is not supported")
+	}
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), "config.json")
+	t.Nil(os.WriteFile(name, []byte(`{}`), 0o600))
+	var reloads atomic.Int32
+	load := loadReloadCfg(name)
+	r, err := appcfg.NewReloader(func() (*reloadCfg, error) {
+		reloads.Add(1)
+		return load()
+	})
+	t.Nil(err)
+
+	c := make(chan os.Signal, 1) // Avoid exit on SIGHUP before WatchSignal starts.
+	signal.Notify(c, syscall.SIGHUP)
+	defer signal.Stop(c)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	errs := make(chan error, 1)
+	go r.WatchSignal(ctx, func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+
+	t.Nil(os.WriteFile(name, []byte(`{"rate_limit":"x"}`), 0o600))
+	proc, err := os.FindProcess(os.Getpid())
+	t.Nil(err)
+	var reloadErr error
+	for reloadErr == nil {
+		t.Nil(proc.Signal(syscall.SIGHUP))
+		select {
+		case reloadErr = <-errs:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Match(reloadErr, `^RateLimit .*"x": invalid syntax`)
+	t.True(reloads.Load() > 1)
+	t.Equal(r.Get().RateLimit.String(), "10")
+}
+
+func TestReloaderConcurrent(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	type cfgT struct {
+		Level appcfg.Int      `key:"level"`
+		Ports appcfg.IntSlice `key:"ports"`
+		Hosts appcfg.StringSlice
+	}
+	var n atomic.Int32
+	r, err := appcfg.NewReloader(func() (*cfgT, error) {
+		cfg := &cfgT{Hosts: appcfg.MustStringSlice("a")}
+		level := strconv.Itoa(int(n.Add(1)))
+		err := appcfg.ProvideStruct(cfg, fromMap{"level": level, "ports": level + "," + level})
+		if err != nil {
+			return nil, err
+		}
+		return cfg, cfg.Hosts.Set("b") // Not completed after Set.
+	})
+	t.Nil(err)
+
+	const readers = 4
+	var (
+		wg    sync.WaitGroup
+		reads atomic.Int64
+	)
+	ctx, cancel := context.WithCancel(t.Context())
+	for range readers {
+		wg.Go(func() {
+			for ; ctx.Err() == nil; reads.Add(1) {
+				cfg := r.Get()
+				var err error
+				level := cfg.Level.Value(&err)
+				ports := cfg.Ports.Value(&err)
+				_ = cfg.Hosts.Get()
+				_ = cfg.Hosts.String()
+				if err != nil || len(ports) != 2 || ports[0] != level || ports[1] != level {
+					t.Errorf("inconsistent cfg: level=%d ports=%v err=%v", level, ports, err)
+				}
+				ports[0] = -1 // Must not change cfg.
+				hosts, _ := cfg.Hosts.Get().([]string)
+				hosts[0] = ""
+				runtime.Gosched()
+			}
+		})
+	}
+	for range 100 {
+		changed, err := r.Reload()
+		t.Nil(err)
+		t.DeepEqual(changed, []string{"Level", "Ports"})
+		for n := reads.Load(); reads.Load() < n+2*readers; { // Let readers read same cfg.
+			runtime.Gosched()
+		}
+	}
+	cancel()
+	wg.Wait()
+	t.Equal(r.Get().Level.String(), "101")
+	t.DeepEqual(r.Get().Hosts.Get(), []string{"b"})
+}
+
+func TestReloaderNotifyOrder(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	type cfgT struct {
+		Level appcfg.Int `key:"level"`
+	}
+	var n atomic.Int32
+	r, err := appcfg.NewReloader(func() (*cfgT, error) {
+		cfg := &cfgT{}
+		return cfg, appcfg.ProvideStruct(cfg, fromMap{"level": strconv.Itoa(int(n.Add(1)))})
+	})
+	t.Nil(err)
+
+	var (
+		active   atomic.Bool
+		levels   []int
+		reloaded bool
+	)
+	r.Subscribe(func(cfg *cfgT, changed []string) {
+		if !active.CompareAndSwap(false, true) {
+			t.Errorf("subscriber called concurrently")
+		}
+		defer active.Store(false)
+		t.DeepEqual(changed, []string{"Level"})
+		var err error
+		levels = append(levels, cfg.Level.Value(&err))
+		if !reloaded { // Reload from subscriber must not deadlock.
+			reloaded = true
+			_, err := r.Reload()
+			t.Nil(err)
+		}
+		runtime.Gosched()
+	})
+
+	const (
+		reloaders = 8
+		reloads   = 50
+	)
+	var wg sync.WaitGroup
+	for range reloaders {
+		wg.Go(func() {
+			for range reloads {
+				_, err := r.Reload()
+				t.Nil(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	t.Len(levels, reloaders*reloads+1)
+	for i := range levels {
+		t.Equal(levels[i], i+2)
+	}
+	t.Equal(r.Get().Level.String(), strconv.Itoa(reloaders*reloads+2))
+}
@@ -0,0 +1,154 @@
+package appcfg
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONSchema returns JSON Schema (draft 2020-12) for a document with
+// fields of cfg (see ProvideStruct) in a dotted path (like "db.host")
+// defined by tag (like "json", "yaml" or "toml" for FromJSON, FromYAML
+// or FromTOML). Fields without tag are ignored.
+//
+// Each Value type provided by this package is described by schema with
+// its constraints (like integer 1..65535 for Port or enum for
+// OneOfString), slice types are described as arrays. Other Value types
+// are described by empty schema (any value). Fields with tag
+// `required:"true"` are marked as required, other "required*" tags are
+// ignored. Value of tag "usage" or "desc" is used as a description.
+//
+// Result can be encoded using [encoding/json.Marshal].
+//
+// Panics if path of one field is a prefix of path of another field (like
+// "extra" and "extra.a").
+func JSONSchema(tag string, cfg any) map[string]any {
+	root := map[string]any{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"type":       "object",
+		"properties": map[string]any{},
+	}
+	values := make(map[string]string)  // Path -> field description.
+	objects := make(map[string]string) // Path -> field description.
+	forStruct(cfg, func(value Value, name string, tags Tags) {
+		path, _, _ := strings.Cut(tags.Get(tag), ",")
+		if path == "" || path == "-" {
+			return
+		}
+		field := fmt.Sprintf("cfg.%s (%s:%q)", name, tag, path)
+		obj := root
+		keys := strings.Split(path, ".")
+		for i, key := range keys[:len(keys)-1] {
+			prefix := strings.Join(keys[:i+1], ".")
+			if other, ok := values[prefix]; ok {
+				panic(fmt.Sprintf("%s: conflicts with %s", field, other))
+			}
+			if _, ok := objects[prefix]; !ok {
+				objects[prefix] = field
+			}
+			props := obj["properties"].(map[string]any) //nolint:forcetypeassert // Want panic.
+			if props[key] == nil {
+				props[key] = map[string]any{"type": "object", "properties": map[string]any{}}
+			}
+			obj = props[key].(map[string]any) //nolint:forcetypeassert // Want panic.
+		}
+		key := keys[len(keys)-1]
+		if other, ok := objects[path]; ok {
+			panic(fmt.Sprintf("%s: conflicts with %s", field, other))
+		}
+		values[path] = field
+
+		schema := map[string]any{}
+		if s, ok := value.(interface{ jsonSchema() map[string]any }); ok {
+			schema = s.jsonSchema()
+		}
+		if isSecret(value) {
+			schema["writeOnly"] = true
+		}
+		if desc := tags.Get("usage"); desc != "" {
+			schema["description"] = desc
+		} else if desc = tags.Get("desc"); desc != "" {
+			schema["description"] = desc
+		}
+		obj["properties"].(map[string]any)[key] = schema //nolint:forcetypeassert // Want panic.
+
+		if required, _ := strconv.ParseBool(tags.Get("required")); required {
+			reqs, _ := obj["required"].([]string)
+			if !slices.Contains(reqs, key) {
+				obj["required"] = append(reqs, key)
+			}
+		}
+	})
+	return root
+}
+
+// schemaParser may be implemented by parser to describe valid values
+// using JSON Schema.
+type schemaParser interface {
+	schema() map[string]any
+}
+
+func parserSchema(p any) map[string]any {
+	if s, ok := p.(schemaParser); ok {
+		return s.schema()
+	}
+	return map[string]any{}
+}
+
+func (v *scalar[T, P]) jsonSchema() map[string]any { return parserSchema(v.parser) }
+
+func (v *slice[T, P]) jsonSchema() map[string]any {
+	return map[string]any{"type": "array", "items": parserSchema(v.parser)}
+}
+
+func (p array[T, P]) schema() map[string]any { return parserSchema(p.parser) }
+
+// durationPattern matches strings valid for [time.ParseDuration].
+const durationPattern = `^[-+]?(0|((\d+(\.\d*)?|\.\d+)(ns|us|µs|μs|ms|s|m|h))+)$`
+
+func (durationParser) schema() map[string]any {
+	return map[string]any{"type": "string", "pattern": durationPattern}
+}
+
+func (boolParser) schema() map[string]any { return map[string]any{"type": "boolean"} }
+
+func (stringParser) schema() map[string]any { return map[string]any{"type": "string"} }
+
+func (notEmptyStringParser) schema() map[string]any {
+	return map[string]any{"type": "string", "pattern": `\S`}
+}
+
+func (p oneOfParser) schema() map[string]any {
+	return map[string]any{"type": "string", "enum": slices.Clone(p.oneOf)}
+}
+
+func (endpointParser) schema() map[string]any {
+	return map[string]any{"type": "string", "format": "uri"}
+}
+
+func (intParser) schema() map[string]any { return map[string]any{"type": "integer"} }
+
+func (int64Parser) schema() map[string]any { return map[string]any{"type": "integer"} }
+
+func (uintParser) schema() map[string]any { return map[string]any{"type": "integer", "minimum": 0} }
+
+func (uint64Parser) schema() map[string]any { return map[string]any{"type": "integer", "minimum": 0} }
+
+func (float64Parser) schema() map[string]any { return map[string]any{"type": "number"} }
+
+func (p intBetweenParser) schema() map[string]any {
+	return map[string]any{"type": "integer", "minimum": p.min, "maximum": p.max}
+}
+
+func (portParser) schema() map[string]any {
+	return map[string]any{"type": "integer", "minimum": 1, "maximum": 65535}
+}
+
+func (listenPortParser) schema() map[string]any {
+	return map[string]any{"type": "integer", "minimum": 0, "maximum": 65535}
+}
+
+func (ipNetParser) schema() map[string]any { return map[string]any{"type": "string"} }
+
+func (hostPortParser) schema() map[string]any { return map[string]any{"type": "string"} }
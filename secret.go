@@ -0,0 +1,121 @@
+package appcfg
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// secretMask is used instead of secret value in output.
+const secretMask = "******"
+
+// secret is implemented by values which should not be shown in output
+// (flag usage, logs, error messages, Source, etc.).
+type secret interface {
+	IsSecret() bool
+}
+
+func isSecret(value Value) bool {
+	s, ok := value.(secret)
+	return ok && s.IsSecret()
+}
+
+// quote returns s quoted for error message or mask if value is secret.
+func quote(value Value, s string) string {
+	if isSecret(value) {
+		return secretMask
+	}
+	return strconv.Quote(s)
+}
+
+//nolint:gochecknoglobals // By design.
+var (
+	_ Value = &SecretString{}
+	_ Value = &NotEmptySecret{}
+)
+
+// SecretString can be set to any string, like String, but it is masked
+// in String, fmt, [slog] and error messages output. Use Value or Get to
+// access real value.
+type SecretString struct {
+	scalar[string, stringParser]
+}
+
+// MustSecretString returns SecretString initialized with given value or panics.
+func MustSecretString(s string) SecretString { return must(SecretString{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*SecretString) Type() string { return "SecretString" }
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *SecretString) Value(err *error) string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
+// IsSecret returns true.
+func (SecretString) IsSecret() bool { return true }
+
+// String implements [flag.Value] interface. It returns mask if value is
+// set or empty string if value is unset.
+func (v *SecretString) String() string { return mask(v != nil && v.value != nil) }
+
+// Format implements [fmt.Formatter] interface.
+func (v SecretString) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, v.GoString())
+	} else {
+		fmt.Fprint(f, v.String())
+	}
+}
+
+// GoString implements [fmt.GoStringer] interface.
+func (v SecretString) GoString() string { return "appcfg.SecretString{" + v.String() + "}" }
+
+// LogValue implements [slog.LogValuer] interface.
+func (v SecretString) LogValue() slog.Value { return slog.StringValue(v.String()) }
+
+// NotEmptySecret can be set to any string which contains at least one
+// non-whitespace symbol, like NotEmptyString, but it is masked in
+// String, fmt, [slog] and error messages output. Use Value or Get to
+// access real value.
+type NotEmptySecret struct {
+	scalar[string, notEmptyStringParser]
+}
+
+// MustNotEmptySecret returns NotEmptySecret initialized with given value or panics.
+func MustNotEmptySecret(s string) NotEmptySecret { return must(NotEmptySecret{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*NotEmptySecret) Type() string { return "NotEmptySecret" }
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *NotEmptySecret) Value(err *error) string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
+// IsSecret returns true.
+func (NotEmptySecret) IsSecret() bool { return true }
+
+// String implements [flag.Value] interface. It returns mask if value is
+// set or empty string if value is unset.
+func (v *NotEmptySecret) String() string { return mask(v != nil && v.value != nil) }
+
+// Format implements [fmt.Formatter] interface.
+func (v NotEmptySecret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, v.GoString())
+	} else {
+		fmt.Fprint(f, v.String())
+	}
+}
+
+// GoString implements [fmt.GoStringer] interface.
+func (v NotEmptySecret) GoString() string { return "appcfg.NotEmptySecret{" + v.String() + "}" }
+
+// LogValue implements [slog.LogValuer] interface.
+func (v NotEmptySecret) LogValue() slog.Value { return slog.StringValue(v.String()) }
+
+func mask(isSet bool) string {
+	if isSet {
+		return secretMask
+	}
+	return ""
+}
@@ -1,410 +1,491 @@
 package appcfg
 
 import (
-	"fmt"
-	"math"
 	"net"
-	"net/url"
-	"strconv"
-	"strings"
 	"time"
 )
 
+//nolint:gochecknoglobals // Compile-time interface checks.
+var (
+	_ Value = &DurationSlice{}
+	_ Value = &BoolSlice{}
+	_ Value = &StringArray{}
+	_ Value = &StringSlice{}
+	_ Value = &NotEmptyStringArray{}
+	_ Value = &NotEmptyStringSlice{}
+	_ Value = &OneOfStringSlice{}
+	_ Value = &EndpointSlice{}
+	_ Value = &IntSlice{}
+	_ Value = &Int64Slice{}
+	_ Value = &UintSlice{}
+	_ Value = &Uint64Slice{}
+	_ Value = &Float64Slice{}
+	_ Value = &IntBetweenSlice{}
+	_ Value = &PortSlice{}
+	_ Value = &ListenPortSlice{}
+	_ Value = &IPNetSlice{}
+	_ Value = &HostPortSlice{}
+)
+
 // DurationSlice can be set to comma-separated strings valid for [time.ParseDuration].
 type DurationSlice struct {
-	values    []time.Duration
-	completed bool
+	slice[time.Duration, durationParser]
 }
 
-func (v *DurationSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []time.Duration{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		d, err := time.ParseDuration(s)
-		if err != nil {
-			return err
-		}
-		v.values = append(v.values, d)
+// MustDurationSlice returns DurationSlice initialized with given values or panics.
+func MustDurationSlice(ss ...string) DurationSlice { return must(DurationSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*DurationSlice) Type() string { return "DurationSlice" }
+
+// String implements [flag.Value] interface.
+func (v *DurationSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *DurationSlice) Value(err *error) []time.Duration { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // BoolSlice can be set to comma-separated strings
 // 1, t, T, TRUE, true, True, 0, f, F, FALSE, false, False.
 type BoolSlice struct {
-	values    []bool
-	completed bool
+	slice[bool, boolParser]
 }
 
-func (v *BoolSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []bool{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		b, err := strconv.ParseBool(s)
-		if err != nil {
-			return err
-		}
-		v.values = append(v.values, b)
+// MustBoolSlice returns BoolSlice initialized with given values or panics.
+func MustBoolSlice(ss ...string) BoolSlice { return must(BoolSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*BoolSlice) Type() string { return "BoolSlice" }
+
+// String implements [flag.Value] interface.
+func (v *BoolSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
+}
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *BoolSlice) Value(err *error) []bool { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
+// IsBoolFlag implements extended [flag.Value] interface.
+func (*BoolSlice) IsBoolFlag() bool {
+	return true
 }
 
 // StringArray can be set to any strings, even empty.
 type StringArray struct {
-	values    []string
-	completed bool
+	slice[string, array[string, stringParser]]
 }
 
-func (v *StringArray) set(s string) error {
-	v.values = append(v.values, s)
-	return nil
+// MustStringArray returns StringArray initialized with given values or panics.
+func MustStringArray(ss ...string) StringArray { return must(StringArray{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*StringArray) Type() string { return "StringArray" }
+
+// String implements [flag.Value] interface.
+func (v *StringArray) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *StringArray) Value(err *error) []string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // StringSlice can be set to any comma-separated strings, even empty.
 type StringSlice struct {
-	values    []string
-	completed bool
+	slice[string, stringParser]
 }
 
-func (v *StringSlice) set(ss string) error {
-	v.values = append(v.values, strings.Split(ss, ",")...)
-	return nil
+// MustStringSlice returns StringSlice initialized with given values or panics.
+func MustStringSlice(ss ...string) StringSlice { return must(StringSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*StringSlice) Type() string { return "StringSlice" }
+
+// String implements [flag.Value] interface.
+func (v *StringSlice) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *StringSlice) Value(err *error) []string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // NotEmptyStringArray can be set to any strings which contains at least one
 // non-whitespace symbol.
 type NotEmptyStringArray struct {
-	values    []string
-	completed bool
+	slice[string, array[string, notEmptyStringParser]]
 }
 
-func (v *NotEmptyStringArray) set(s string) error {
-	if v.values == nil && s == "" {
-		v.values = []string{}
-		return nil
-	}
-	if strings.TrimSpace(s) == "" {
-		return errEmptyOrWhite
+// MustNotEmptyStringArray returns NotEmptyStringArray initialized with given values or panics.
+func MustNotEmptyStringArray(ss ...string) NotEmptyStringArray {
+	return must(NotEmptyStringArray{}, ss...)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*NotEmptyStringArray) Type() string { return "NotEmptyStringArray" }
+
+// String implements [flag.Value] interface.
+func (v *NotEmptyStringArray) String() string {
+	if v == nil {
+		return ""
 	}
-	v.values = append(v.values, s)
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *NotEmptyStringArray) Value(err *error) []string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // NotEmptyStringSlice can be set to any comma-separated strings which contains at least one
 // non-whitespace symbol.
 type NotEmptyStringSlice struct {
-	values    []string
-	completed bool
+	slice[string, notEmptyStringParser]
 }
 
-func (v *NotEmptyStringSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []string{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		if strings.TrimSpace(s) == "" {
-			return errEmptyOrWhite
-		}
-		v.values = append(v.values, s)
+// MustNotEmptyStringSlice returns NotEmptyStringSlice initialized with given values or panics.
+func MustNotEmptyStringSlice(ss ...string) NotEmptyStringSlice {
+	return must(NotEmptyStringSlice{}, ss...)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*NotEmptyStringSlice) Type() string { return "NotEmptyStringSlice" }
+
+// String implements [flag.Value] interface.
+func (v *NotEmptyStringSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *NotEmptyStringSlice) Value(err *error) []string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // OneOfStringSlice can be set to predefined (using NewOneOfString or MustOneOfString)
 // comma-separated strings.
 type OneOfStringSlice struct {
-	values    []string
-	completed bool
-	oneOf     []string
+	slice[string, oneOfParser]
 }
 
-func (v *OneOfStringSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []string{}
-		return nil
-	}
-SET:
-	for s := range strings.SplitSeq(ss, ",") {
-		for _, item := range v.oneOf {
-			if s == item {
-				v.values = append(v.values, s)
-				continue SET
-			}
-		}
-		return fmt.Errorf("%w %q", errNotOneOf, v.oneOf)
+// NewOneOfStringSlice returns OneOfStringSlice without value set.
+func NewOneOfStringSlice(oneOf []string) OneOfStringSlice {
+	return OneOfStringSlice{slice[string, oneOfParser]{parser: oneOfParser{oneOf: oneOf}}}
+}
+
+// MustOneOfStringSlice returns OneOfStringSlice initialized with given value or panics.
+func MustOneOfStringSlice(oneOf []string, ss ...string) OneOfStringSlice {
+	return must(NewOneOfStringSlice(oneOf), ss...)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*OneOfStringSlice) Type() string { return "OneOfStringSlice" }
+
+// String implements [flag.Value] interface.
+func (v *OneOfStringSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *OneOfStringSlice) Value(err *error) []string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // EndpointSlice can be set to valid urls with hostname. Also it'll trim
 // all / symbols at end, to make it easier to append paths to endpoint.
 type EndpointSlice struct {
-	values    []string
-	completed bool
+	slice[string, array[string, endpointParser]]
 }
 
-func (v *EndpointSlice) set(s string) error {
-	if v.values == nil && s == "" {
-		v.values = []string{}
-		return nil
-	}
-	s = strings.TrimRight(s, "/")
-	p, err := url.Parse(s)
-	if err != nil {
-		return err
-	} else if p.Host == "" {
-		return errNoHost
+// MustEndpointSlice returns EndpointSlice initialized with given values or panics.
+func MustEndpointSlice(ss ...string) EndpointSlice { return must(EndpointSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*EndpointSlice) Type() string { return "EndpointSlice" }
+
+// String implements [flag.Value] interface.
+func (v *EndpointSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	v.values = append(v.values, s)
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *EndpointSlice) Value(err *error) []string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // IntSlice can be set to comma-separated integer values.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type IntSlice struct {
-	values    []int
-	completed bool
+	slice[int, intParser]
 }
 
-func (v *IntSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []int{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i64, err := strconv.ParseInt(s, 0, strconv.IntSize)
-		if err != nil {
-			return err
-		}
-		i := int(i64)
-		if int64(i) != i64 {
-			return fmt.Errorf("%w int: %s", errOverflows, s)
-		}
-		v.values = append(v.values, i)
+// MustIntSlice returns IntSlice initialized with given values or panics.
+func MustIntSlice(ss ...string) IntSlice { return must(IntSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*IntSlice) Type() string { return "IntSlice" }
+
+// String implements [flag.Value] interface.
+func (v *IntSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *IntSlice) Value(err *error) []int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Int64Slice can be set to comma-separated 64-bit integer values.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type Int64Slice struct {
-	values    []int64
-	completed bool
+	slice[int64, int64Parser]
 }
 
-func (v *Int64Slice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []int64{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i, err := strconv.ParseInt(s, 0, parseBits)
-		if err != nil {
-			return err
-		}
-		v.values = append(v.values, i)
+// MustInt64Slice returns Int64Slice initialized with given values or panics.
+func MustInt64Slice(ss ...string) Int64Slice { return must(Int64Slice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Int64Slice) Type() string { return "Int64Slice" }
+
+// String implements [flag.Value] interface.
+func (v *Int64Slice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Int64Slice) Value(err *error) []int64 { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // UintSlice can be set to comma-separated unsigned integer values.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type UintSlice struct {
-	values    []uint
-	completed bool
+	slice[uint, uintParser]
 }
 
-func (v *UintSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []uint{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i64, err := strconv.ParseUint(s, 0, strconv.IntSize)
-		if err != nil {
-			return err
-		}
-		i := uint(i64)
-		if uint64(i) != i64 {
-			return fmt.Errorf("%w unsigned int: %s", errOverflows, s)
-		}
-		v.values = append(v.values, i)
+// MustUintSlice returns UintSlice initialized with given values or panics.
+func MustUintSlice(ss ...string) UintSlice { return must(UintSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*UintSlice) Type() string { return "UintSlice" }
+
+// String implements [flag.Value] interface.
+func (v *UintSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *UintSlice) Value(err *error) []uint { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Uint64Slice can be set to comma-separated unsigned 64-bit integer values.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type Uint64Slice struct {
-	values    []uint64
-	completed bool
+	slice[uint64, uint64Parser]
 }
 
-func (v *Uint64Slice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []uint64{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i, err := strconv.ParseUint(s, 0, parseBits)
-		if err != nil {
-			return err
-		}
-		v.values = append(v.values, i)
+// MustUint64Slice returns Uint64Slice initialized with given values or panics.
+func MustUint64Slice(ss ...string) Uint64Slice { return must(Uint64Slice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Uint64Slice) Type() string { return "Uint64Slice" }
+
+// String implements [flag.Value] interface.
+func (v *Uint64Slice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Uint64Slice) Value(err *error) []uint64 { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Float64Slice can be set to comma-separated 64-bit floating-point numbers.
 type Float64Slice struct {
-	values    []float64
-	completed bool
+	slice[float64, float64Parser]
 }
 
-func (v *Float64Slice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []float64{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i, err := strconv.ParseFloat(s, parseBits)
-		if err != nil {
-			return err
-		}
-		v.values = append(v.values, i)
+// MustFloat64Slice returns Float64Slice initialized with given values or panics.
+func MustFloat64Slice(ss ...string) Float64Slice { return must(Float64Slice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Float64Slice) Type() string { return "Float64Slice" }
+
+// String implements [flag.Value] interface.
+func (v *Float64Slice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Float64Slice) Value(err *error) []float64 { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // IntBetweenSlice can be set to comma-separated integer values between given (using
 // NewIntBetween or MustIntBetween) min/max values (inclusive).
 type IntBetweenSlice struct {
-	values    []int
-	completed bool
-	min, max  int
+	slice[int, intBetweenParser]
 }
 
-func (v *IntBetweenSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []int{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i, err := strconv.Atoi(s)
-		if err != nil {
-			return err
-		} else if v.min > i || i > v.max {
-			return fmt.Errorf("%w %d and %d", errNotBetween, v.min, v.max)
-		}
-		v.values = append(v.values, i)
+// NewIntBetweenSlice returns IntBetweenSlice without value set.
+func NewIntBetweenSlice(minVal, maxVal int) IntBetweenSlice {
+	return IntBetweenSlice{slice[int, intBetweenParser]{parser: intBetweenParser{min: minVal, max: maxVal}}}
+}
+
+// MustIntBetweenSlice returns IntBetweenSlice initialized with given value or panics.
+func MustIntBetweenSlice(minVal, maxVal int, ss ...string) IntBetweenSlice {
+	return must(NewIntBetweenSlice(minVal, maxVal), ss...)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*IntBetweenSlice) Type() string { return "IntBetweenSlice" }
+
+// String implements [flag.Value] interface.
+func (v *IntBetweenSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *IntBetweenSlice) Value(err *error) []int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // PortSlice can be set to comma-separated integer values between 1 and 65535.
 type PortSlice struct {
-	values    []int
-	completed bool
+	slice[int, portParser]
 }
 
-func (v *PortSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []int{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i, err := strconv.Atoi(s)
-		if err != nil {
-			return err
-		} else if 0 >= i || i > math.MaxUint16 {
-			return fmt.Errorf("%w 1 and %d", errNotBetween, math.MaxUint16)
-		}
-		v.values = append(v.values, i)
+// MustPortSlice returns PortSlice initialized with given values or panics.
+func MustPortSlice(ss ...string) PortSlice { return must(PortSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*PortSlice) Type() string { return "PortSlice" }
+
+// String implements [flag.Value] interface.
+func (v *PortSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *PortSlice) Value(err *error) []int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // ListenPortSlice can be set to comma-separated integer values between 0 and 65535.
 type ListenPortSlice struct {
-	values    []int
-	completed bool
+	slice[int, listenPortParser]
 }
 
-func (v *ListenPortSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []int{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		i, err := strconv.Atoi(s)
-		if err != nil {
-			return err
-		} else if 0 > i || i > math.MaxUint16 {
-			return fmt.Errorf("%w 0 and %d", errNotBetween, math.MaxUint16)
-		}
-		v.values = append(v.values, i)
+// MustListenPortSlice returns ListenPortSlice initialized with given values or panics.
+func MustListenPortSlice(ss ...string) ListenPortSlice { return must(ListenPortSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*ListenPortSlice) Type() string { return "ListenPortSlice" }
+
+// String implements [flag.Value] interface.
+func (v *ListenPortSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *ListenPortSlice) Value(err *error) []int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // IPNetSlice can be set to comma-separated CIDR address.
 type IPNetSlice struct {
-	values    []*net.IPNet
-	completed bool
+	slice[*net.IPNet, ipNetParser]
 }
 
-func (v *IPNetSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []*net.IPNet{}
-		return nil
-	}
-	for s := range strings.SplitSeq(ss, ",") {
-		_, ipNet, err := net.ParseCIDR(s)
-		if err != nil {
-			return err
-		}
-		v.values = append(v.values, ipNet)
+// MustIPNetSlice returns IPNetSlice initialized with given values or panics.
+func MustIPNetSlice(ss ...string) IPNetSlice { return must(IPNetSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*IPNetSlice) Type() string { return "IPNetSlice" }
+
+// String implements [flag.Value] interface.
+func (v *IPNetSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	return nil
+	return v.slice.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *IPNetSlice) Value(err *error) []*net.IPNet { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // HostPortSlice can be set to comma-separated CIDR addresses.
 type HostPortSlice struct {
-	values    []string
-	tuples    []HostPortTuple
-	completed bool
+	slice[string, hostPortParser]
 }
 
-func (v *HostPortSlice) set(ss string) error {
-	if v.values == nil && ss == "" {
-		v.values = []string{}
-		v.tuples = []HostPortTuple{}
-		return nil
+// MustHostPortSlice returns HostPortSlice initialized with given values or panics.
+func MustHostPortSlice(ss ...string) HostPortSlice { return must(HostPortSlice{}, ss...) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*HostPortSlice) Type() string { return "HostPortSlice" }
+
+// String implements [flag.Value] interface.
+func (v *HostPortSlice) String() string {
+	if v == nil {
+		return ""
 	}
-	if v.values == nil {
-		v.tuples = nil
+	return v.slice.String()
+}
+
+// HostPortTuple represents a host and port pair.
+type HostPortTuple struct {
+	Host string
+	Port int
+}
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *HostPortSlice) Value(err *error) []HostPortTuple { //nolint:gocritic // ptrToRefParam.
+	values := v.get(err, v)
+	if values == nil {
+		return nil
 	}
-	for s := range strings.SplitSeq(ss, ",") {
-		var tuple HostPortTuple
-		host, port, err := net.SplitHostPort(s)
-		if err != nil {
-			return err
-		}
-		if host == "" {
-			return errNoHost
-		}
-		tuple.Host = host
-		tuple.Port, err = strconv.Atoi(port)
-		if err != nil {
-			return fmt.Errorf("port: %w", err)
-		}
-		v.values = append(v.values, s)
-		v.tuples = append(v.tuples, tuple)
+	tuples := make([]HostPortTuple, len(values))
+	for i, s := range values {
+		tuples[i], _ = splitHostPort(s) // Already validated by Set.
 	}
-	return nil
+	return tuples
 }
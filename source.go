@@ -0,0 +1,192 @@
+package appcfg
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+)
+
+// Kinds of Source.
+const (
+	SourceUnset   = ""        // Value is not set.
+	SourceDefault = "default" // Value was set before applying providers and flags or by FromDefault.
+	SourceEnv     = "env"     // Value was set from environment variable.
+	SourceFile    = "file"    // Value was set from a file.
+	SourceFlag    = "flag"    // Value was set from a command line flag.
+)
+
+// Source describes where the current value of some Value came from.
+type Source struct {
+	// Kind is one of Source* constants or name of type of custom
+	// Provider which does not report Source.
+	Kind string
+	// Key is environment variable name (like "$EXAMPLE_PORT"), file
+	// path and key or path in a file (like "config.json:db.port"),
+	// flag name (like "-port"), etc. It may be empty for SourceDefault
+	// and custom Provider.
+	Key string
+	// Raw is a raw string used to set value (elements of array in a
+	// file are joined with ","). For SourceDefault, SourceFlag and
+	// custom Provider it's a current value. It's masked for secret
+	// values (like SecretString).
+	Raw string
+}
+
+// String returns Source in human-readable format.
+func (s Source) String() string {
+	switch {
+	case s.Kind == SourceUnset:
+		return "unset"
+	case s.Key == "":
+		return fmt.Sprintf("%s %q", s.Kind, s.Raw)
+	default:
+		return fmt.Sprintf("%s %s=%q", s.Kind, s.Key, s.Raw)
+	}
+}
+
+// sourceTracker keeps track of where the current value of Value came
+// from. It's embedded in all Value types provided by this package, so
+// recorded source is reset by Set and has same lifetime as the value.
+//
+// It must be comparable to keep Value types comparable.
+type sourceTracker struct {
+	src  *Source      // Recorded by Provider.
+	flag *flagBinding // Recorded by AddFlag, AddPFlag, AddFlags or AddPFlags.
+}
+
+func (t *sourceTracker) tracker() *sourceTracker { return t }
+
+// flagBinding describes a flag defined for a value in fs or pfs.
+type flagBinding struct {
+	fs   *flag.FlagSet
+	pfs  *pflag.FlagSet
+	name string
+}
+
+// key returns flag name with dashes (like "-port").
+func (b *flagBinding) key() string {
+	if b.pfs != nil {
+		return "--" + b.name
+	}
+	return "-" + b.name
+}
+
+// isSet returns true if flag was set to value on parsing command line.
+func (b *flagBinding) isSet(value Value) (ok bool) {
+	if b.pfs != nil {
+		f := b.pfs.Lookup(b.name)
+		return f != nil && f.Value == value && f.Changed
+	}
+	b.fs.Visit(func(f *flag.Flag) {
+		ok = ok || f.Name == b.name && f.Value == value
+	})
+	return ok
+}
+
+// tracked is implemented by Value which keeps track of its source.
+type tracked interface {
+	tracker() *sourceTracker
+}
+
+// setSource records the source of value. It should be called by Provider
+// after successfully setting value.
+func setSource(value Value, kind, key, raw string) {
+	t, ok := value.(tracked)
+	if !ok {
+		return
+	}
+	if isSecret(value) {
+		raw = secretMask
+	}
+	t.tracker().src = &Source{Kind: kind, Key: key, Raw: raw}
+}
+
+// hasSource returns true if source of current value was recorded by
+// Provider or value does not keep track of its source.
+func hasSource(value Value) bool {
+	t, ok := value.(tracked)
+	return !ok || t.tracker().src != nil
+}
+
+// SourceOf returns the source of current value, recorded by ProvideStruct
+// (and Providers) and flags defined using AddFlag, AddPFlag, AddFlags or
+// AddPFlags.
+//
+// Source is kept inside the value, so it's reset by Set (e.g. value set
+// by Set called outside of Provider has SourceDefault) and by assigning
+// a new value (e.g. zero or returned by MustPort). Only Value types
+// provided by this package keep track of their source, for other types
+// SourceOf returns SourceDefault for any set value.
+func SourceOf(value Value) Source {
+	if value.Get() == nil {
+		return Source{Kind: SourceUnset}
+	}
+	if t, ok := value.(tracked); ok {
+		t := t.tracker()
+		if t.flag != nil && t.flag.isSet(value) {
+			return Source{Kind: SourceFlag, Key: t.flag.key(), Raw: value.String()}
+		}
+		if t.src != nil {
+			return *t.src
+		}
+	}
+	return Source{Kind: SourceDefault, Raw: value.String()}
+}
+
+// FieldSource describes source of some cfg field.
+type FieldSource struct {
+	Field  string // Field name.
+	Tags   Tags   // Field tags.
+	Source Source
+}
+
+// Sources returns sources of all fields in given cfgs (see ProvideStruct).
+func Sources(cfgs ...any) []FieldSource {
+	var fieldSources []FieldSource
+	for _, cfg := range cfgs {
+		forStruct(cfg, func(value Value, name string, tags Tags) {
+			fieldSources = append(fieldSources, FieldSource{Field: name, Tags: tags, Source: SourceOf(value)})
+		})
+	}
+	return fieldSources
+}
+
+// DumpSources writes sources of all fields in given cfgs (see
+// ProvideStruct) to w, one field per line.
+func DumpSources(w io.Writer, cfgs ...any) error {
+	for _, src := range Sources(cfgs...) {
+		_, err := fmt.Fprintf(w, "%s: %s\n", src.Field, src.Source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flagName returns name (like "-port") of a flag defined for value using
+// AddFlag, AddPFlag, AddFlags or AddPFlags or empty string. If value was
+// added to several FlagSet then last one is used.
+func flagName(value Value) string {
+	if t, ok := value.(tracked); ok && t.tracker().flag != nil {
+		return t.tracker().flag.key()
+	}
+	return ""
+}
+
+func setFlagSource(fs *flag.FlagSet, value Value, name string) {
+	t, ok := value.(tracked)
+	if !ok {
+		return
+	}
+	t.tracker().flag = &flagBinding{fs: fs, name: name}
+}
+
+func setPFlagSource(fs *pflag.FlagSet, value Value, name string) {
+	t, ok := value.(tracked)
+	if !ok {
+		return
+	}
+	t.tracker().flag = &flagBinding{pfs: fs, name: name}
+}
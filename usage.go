@@ -0,0 +1,140 @@
+package appcfg
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// FlagUsage returns function suitable for [flag.FlagSet.Usage], which
+// prints usage message with all fields in cfgs (see ProvideStruct)
+// which have flag in fs or tag "env" (with envPrefix, same as used for
+// FromEnv), followed by all other flags in fs.
+//
+// Each setting is printed once with its flag, env, type, usage and
+// details: default value, allowed values and required-ness (see Docs).
+func FlagUsage(fs *flag.FlagSet, envPrefix string, cfgs ...any) func() {
+	return func() {
+		flags := make(map[Value]string)
+		fs.VisitAll(func(f *flag.Flag) {
+			if value, ok := f.Value.(Value); ok {
+				flags[value] = "-" + f.Name
+			}
+		})
+		var b strings.Builder
+		printUsageHeader(&b, fs.Name())
+		seen := printUsageFields(&b, envPrefix, flags, cfgs)
+		fs.VisitAll(func(f *flag.Flag) {
+			if value, ok := f.Value.(Value); ok && seen[value] {
+				return
+			}
+			typ, usage := flag.UnquoteUsage(f)
+			printUsage(&b, "-"+f.Name, typ, usage, usageDetails(flagDefault(f.DefValue), "", ""))
+		})
+		_, _ = io.WriteString(fs.Output(), b.String())
+	}
+}
+
+// PFlagUsage is like FlagUsage but for [pflag.FlagSet.Usage].
+func PFlagUsage(fs *pflag.FlagSet, envPrefix string, cfgs ...any) func() {
+	name := func(f *pflag.Flag) string {
+		if f.Shorthand != "" {
+			return "-" + f.Shorthand + ", --" + f.Name
+		}
+		return "--" + f.Name
+	}
+	return func() {
+		flags := make(map[Value]string)
+		fs.VisitAll(func(f *pflag.Flag) {
+			if value, ok := f.Value.(Value); ok {
+				flags[value] = name(f)
+			}
+		})
+		var b strings.Builder
+		printUsageHeader(&b, fs.Name())
+		seen := printUsageFields(&b, envPrefix, flags, cfgs)
+		fs.VisitAll(func(f *pflag.Flag) {
+			if value, ok := f.Value.(Value); (ok && seen[value]) || f.Hidden {
+				return
+			}
+			typ, usage := pflag.UnquoteUsage(f)
+			printUsage(&b, name(f), typ, usage, usageDetails(flagDefault(f.DefValue), "", ""))
+		})
+		_, _ = io.WriteString(fs.Output(), b.String())
+	}
+}
+
+func printUsageHeader(b *strings.Builder, name string) {
+	if name == "" {
+		b.WriteString("Usage:\n")
+	} else {
+		fmt.Fprintf(b, "Usage of %s:\n", name)
+	}
+}
+
+// printUsageFields prints usage for cfgs fields which have flag (in
+// flags) or env and returns all printed values.
+func printUsageFields(b *strings.Builder, envPrefix string, flags map[Value]string, cfgs []any) map[Value]bool {
+	seen := make(map[Value]bool)
+	for _, cfg := range cfgs {
+		forStruct(cfg, func(value Value, name string, tags Tags) {
+			doc := fieldDoc(envPrefix, value, name, tags)
+			var names []string
+			if flags[value] != "" {
+				names = append(names, flags[value])
+			}
+			if doc.Env != "" {
+				names = append(names, "$"+doc.Env)
+			}
+			if len(names) == 0 || seen[value] {
+				return
+			}
+			seen[value] = true
+			printUsage(b, strings.Join(names, ", "), doc.Type, doc.Usage,
+				usageDetails(doc.Default, doc.Allowed, doc.Required))
+		})
+	}
+	return seen
+}
+
+// flagDefault returns def or empty string if def is a zero value of
+// standard flag type.
+func flagDefault(def string) string {
+	switch def {
+	case "false", "0", "[]":
+		return ""
+	}
+	return def
+}
+
+func usageDetails(def, allowed, required string) string {
+	var details []string
+	if def != "" {
+		details = append(details, fmt.Sprintf("default %q", def))
+	}
+	if allowed != "" {
+		details = append(details, "allowed: "+allowed)
+	}
+	if required != "" {
+		details = append(details, "required: "+required)
+	}
+	return strings.Join(details, "; ")
+}
+
+// printUsage prints usage in same format as [flag.PrintDefaults].
+func printUsage(b *strings.Builder, names, typ, usage, details string) {
+	fmt.Fprintf(b, "  %s", names)
+	if typ != "" {
+		fmt.Fprintf(b, " %s", typ)
+	}
+	if details != "" {
+		usage = strings.TrimSpace(usage + " (" + details + ")")
+	}
+	if usage != "" {
+		fmt.Fprintf(b, "\n    \t%s", strings.ReplaceAll(usage, "\n", "\n    \t"))
+	}
+	b.WriteString("\n")
+}
@@ -0,0 +1,89 @@
+package appcfg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Validate returns error for each unset required field in cfg (see
+// ProvideStruct). All such errors are returned joined using [errors.Join],
+// each one is a FieldError with RequiredError, use FieldErrors to get them.
+//
+// Field is required if it has tag `required:"true"`, or tag "required_if"
+// has a true condition, or tag "required_unless" has no true conditions.
+// Conditions are separated by space, condition "Field=value" is true if
+// current value of other Field has given value, condition "Field" is true
+// if other Field is set. Field is looked up first in same struct, then
+// from the top of cfg, e.g. `required_if:"Mode=tls Mode=mtls"`.
+//
+// Panics on invalid tag value or unknown field in condition.
+func Validate(cfg any) error {
+	return validateRequired(cfg, flagName)
+}
+
+// validateRequired is like Validate but uses flagName to get flag names.
+func validateRequired(cfg any, flagName func(Value) string) error {
+	type fieldValue struct {
+		Value
+		name string
+		tags Tags
+	}
+	var fields []fieldValue
+	values := make(map[string]Value)
+	forStruct(cfg, func(value Value, name string, tags Tags) {
+		fields = append(fields, fieldValue{value, name, tags})
+		values[name] = value
+	})
+	var errs []error
+	for _, f := range fields {
+		if isRequired(f.name, f.tags, values) && f.Get() == nil {
+			errs = append(errs, &FieldError{
+				Field: f.name,
+				Flag:  flagName(f.Value),
+				Tags:  f.tags,
+				Err:   &RequiredError{f.Value},
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isRequired(name string, tags Tags, values map[string]Value) bool {
+	if s, ok := tags.Lookup("required"); ok {
+		required, err := strconv.ParseBool(s)
+		if err != nil {
+			panic(fmt.Sprintf("cfg.%s: required: %s", name, err))
+		}
+		if required {
+			return true
+		}
+	}
+	if s, ok := tags.Lookup("required_if"); ok && anyCondition(name, s, values) {
+		return true
+	}
+	if s, ok := tags.Lookup("required_unless"); ok && !anyCondition(name, s, values) {
+		return true
+	}
+	return false
+}
+
+func anyCondition(name, conditions string, values map[string]Value) bool {
+	path := name[:strings.LastIndex(name, ".")+1]
+	found := false
+	for _, cond := range strings.Fields(conditions) {
+		ref, want, hasWant := strings.Cut(cond, "=")
+		value, ok := values[path+ref]
+		if !ok {
+			value, ok = values[ref]
+		}
+		if !ok {
+			panic(fmt.Sprintf("cfg.%s: unknown field %q in condition", name, ref))
+		}
+		if value.Get() != nil && (!hasWant || value.String() == want) {
+			found = true
+		}
+	}
+	return found
+}
@@ -2,17 +2,23 @@ package appcfg
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"slices"
+	"strings"
 )
 
+// Errors returned by Set (possibly wrapped, with more details) and
+// available in FieldError for matching with [errors.Is].
 var (
-	errEmptyOrWhite = errors.New("empty or contain only whitespaces")
-	errNoHost       = errors.New("no host")
-	errOverflows    = errors.New("value overflows")
-	errNotOneOf     = errors.New("not one of")
-	errNotBetween   = errors.New("not between")
+	ErrEmptyOrWhite = errors.New("empty or contain only whitespaces")
+	ErrNoHost       = errors.New("no host")
+	ErrNotOneOf     = errors.New("not one of")
+	ErrNotBetween   = errors.New("not between")
 )
 
+var errOverflows = errors.New("value overflows")
+
 const parseBits = 64
 
 // Value provides a way to set value of any type from (one or several)
@@ -30,6 +36,10 @@ const parseBits = 64
 //
 // Types containing boolean value should also provide IsBoolFlag() method
 // returning true.
+//
+// Value is not safe for concurrent use if one of goroutines may call Set.
+// To read cfg concurrently with loading a new one use Reloader, it
+// provides immutable snapshot of cfg.
 type Value interface {
 	String() string     // May be called on zero-valued receiver.
 	Set(s string) error // Unset value on error.
@@ -43,3 +53,190 @@ var typValue = reflect.TypeFor[Value]()
 func implementsValue(typ reflect.Type) bool {
 	return typ.Implements(typValue) || reflect.PointerTo(typ).Implements(typValue)
 }
+
+// parser converts string into a value of type T with validation.
+//
+// It is used by types which implements Value for values of type T, so
+// zero value of parser should be usable (unless it's configured by
+// constructor of such type, like NewIntBetween).
+type parser[T any] interface {
+	parse(s string) (T, error)
+}
+
+// arrayParser may be implemented by parser used by slice to disable
+// splitting Set argument by comma.
+type arrayParser interface {
+	array()
+}
+
+// scalar implements most of Value interface (except Type) for single
+// value of type T.
+type scalar[T any, P parser[T]] struct {
+	sourceTracker
+	value  *T
+	parser P
+}
+
+// String implements [flag.Value] interface.
+func (v *scalar[T, P]) String() string {
+	if v.value == nil {
+		return ""
+	}
+	return fmt.Sprint(*v.value)
+}
+
+// Set implements [flag.Value] interface.
+func (v *scalar[T, P]) Set(s string) error {
+	v.src = nil
+	val, err := v.parser.parse(s)
+	if err != nil {
+		v.value = nil
+		return err
+	}
+	v.value = &val
+	return nil
+}
+
+// Get implements [flag.Getter] interface.
+func (v *scalar[T, P]) Get() any {
+	if v.value == nil {
+		return nil
+	}
+	return *v.value
+}
+
+// get is like Get except it returns zero value and set *err to
+// RequiredError for self if unset.
+func (v *scalar[T, P]) get(err *error, self Value) (val T) { //nolint:gocritic // ptrToRefParam.
+	if v.value == nil {
+		*err = &RequiredError{self}
+		return val
+	}
+	return *v.value
+}
+
+// slice implements most of Value interface (except Type) for multiple
+// values of type T.
+type slice[T any, P parser[T]] struct {
+	sourceTracker
+	values    []T
+	completed bool
+	parser    P
+}
+
+// String implements [flag.Value] interface.
+func (v *slice[T, P]) String() string {
+	if v.values == nil {
+		return ""
+	}
+	return fmt.Sprint(v.values)
+}
+
+// text returns values joined by comma (in format accepted by Set) or
+// false if values can't be set by a single Set call (there are several
+// values and parser implements arrayParser).
+func (v *slice[T, P]) text() (string, bool) {
+	if _, ok := any(v.parser).(arrayParser); ok && len(v.values) > 1 {
+		return "", false
+	}
+	ss := make([]string, len(v.values))
+	for i := range v.values {
+		ss[i] = fmt.Sprint(v.values[i])
+	}
+	return strings.Join(ss, ","), true
+}
+
+// Set implements [flag.Value] interface.
+func (v *slice[T, P]) Set(s string) error {
+	v.src = nil
+	if v.completed {
+		v.completed = false
+		v.values = nil
+	}
+	err := v.set(s)
+	if err != nil {
+		v.values = nil
+	}
+	return err
+}
+
+// set appends comma-separated values (or single value if parser
+// implements arrayParser) from ss. Set to empty slice if ss is empty and
+// it's not a valid value.
+func (v *slice[T, P]) set(ss string) error {
+	if v.values == nil && ss == "" {
+		if _, err := v.parser.parse(ss); err != nil {
+			v.values = []T{}
+			return nil
+		}
+	}
+	elems := []string{ss}
+	if _, ok := any(v.parser).(arrayParser); !ok {
+		elems = strings.Split(ss, ",")
+	}
+	for _, s := range elems {
+		val, err := v.parser.parse(s)
+		if err != nil {
+			return err
+		}
+		v.values = append(v.values, val)
+	}
+	return nil
+}
+
+// Get implements [flag.Getter] interface.
+// It returns a copy of values, so it's safe to modify returned slice
+// (but not values referenced by its elements, like *net.IPNet).
+//
+// It modifies v only on first call after Set, so it's safe to call Get
+// concurrently after that (see Reloader).
+func (v *slice[T, P]) Get() any {
+	if v.values == nil {
+		return nil
+	}
+	if !v.completed {
+		v.completed = true
+	}
+	return slices.Clone(v.values)
+}
+
+// get is like Get except it returns zero value and set *err to
+// RequiredError for self if unset.
+func (v *slice[T, P]) get(err *error, self Value) (val []T) { //nolint:gocritic // ptrToRefParam.
+	vals := v.Get()
+	if vals == nil {
+		*err = &RequiredError{self}
+		return val
+	}
+	return vals.([]T) //nolint:forcetypeassert // Want panic.
+}
+
+// array is a parser for slice which does not split Set argument by comma.
+type array[T any, P parser[T]] struct {
+	parser P
+}
+
+func (p array[T, P]) parse(s string) (T, error) { return p.parser.parse(s) }
+
+func (array[T, P]) array() {}
+
+// valuePtr is a pointer to V which implements Value.
+type valuePtr[V any] interface {
+	*V
+	Value
+}
+
+// must returns v after setting given values or panics.
+func must[V any, PV valuePtr[V]](v V, ss ...string) V {
+	if len(ss) == 0 {
+		panic("require at least 1 arg")
+	}
+	for _, s := range ss {
+		err := PV(&v).Set(s)
+		if err != nil {
+			panic(err)
+		}
+	}
+	_ = PV(&v).Get() // Mark as completed (in case it's a slice).
+	return v
+}
@@ -11,257 +11,589 @@ import (
 	"time"
 )
 
+//nolint:gochecknoglobals // Compile-time interface checks.
+var (
+	_ Value = &Duration{}
+	_ Value = &Bool{}
+	_ Value = &String{}
+	_ Value = &NotEmptyString{}
+	_ Value = &OneOfString{}
+	_ Value = &Endpoint{}
+	_ Value = &Int{}
+	_ Value = &Int64{}
+	_ Value = &Uint{}
+	_ Value = &Uint64{}
+	_ Value = &Float64{}
+	_ Value = &IntBetween{}
+	_ Value = &Port{}
+	_ Value = &ListenPort{}
+	_ Value = &IPNet{}
+	_ Value = &HostPort{}
+)
+
 // Duration can be set only to string valid for [time.ParseDuration].
 type Duration struct {
-	value *time.Duration
+	scalar[time.Duration, durationParser]
 }
 
-func (v *Duration) set(s string) error {
-	d, err := time.ParseDuration(s)
-	if err != nil {
-		return err
+type durationParser struct{}
+
+func (durationParser) parse(s string) (time.Duration, error) {
+	return time.ParseDuration(s)
+}
+
+// MustDuration returns Duration initialized with given value or panics.
+func MustDuration(s string) Duration { return must(Duration{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Duration) Type() string { return "Duration" }
+
+// String implements [flag.Value] interface.
+func (v *Duration) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &d
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Duration) Value(err *error) time.Duration { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Bool can be set to 1, t, T, TRUE, true, True, 0, f, F, FALSE, false, False.
 type Bool struct {
-	value *bool
+	scalar[bool, boolParser]
 }
 
-func (v *Bool) set(s string) error {
-	b, err := strconv.ParseBool(s)
-	if err != nil {
-		return err
+type boolParser struct{}
+
+func (boolParser) parse(s string) (bool, error) {
+	return strconv.ParseBool(s)
+}
+
+// MustBool returns Bool initialized with given value or panics.
+func MustBool(s string) Bool { return must(Bool{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Bool) Type() string { return "Bool" }
+
+// String implements [flag.Value] interface.
+func (v *Bool) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &b
-	return nil
+	return v.scalar.String()
+}
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Bool) Value(err *error) bool { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
+// IsBoolFlag implements extended [flag.Value] interface.
+func (*Bool) IsBoolFlag() bool {
+	return true
 }
 
 // String can be set to any string, even empty.
 type String struct {
-	value *string
+	scalar[string, stringParser]
+}
+
+type stringParser struct{}
+
+func (stringParser) parse(s string) (string, error) {
+	return s, nil
 }
 
-func (v *String) set(s string) error {
-	v.value = &s
-	return nil
+// MustString returns String initialized with given value or panics.
+func MustString(s string) String { return must(String{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*String) Type() string { return "String" }
+
+// String implements [flag.Value] interface.
+func (v *String) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *String) Value(err *error) string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // NotEmptyString can be set to any string which contains at least one
 // non-whitespace symbol.
 type NotEmptyString struct {
-	value *string
+	scalar[string, notEmptyStringParser]
 }
 
-func (v *NotEmptyString) set(s string) error {
+type notEmptyStringParser struct{}
+
+func (notEmptyStringParser) parse(s string) (string, error) {
 	if strings.TrimSpace(s) == "" {
-		return errEmptyOrWhite
+		return "", ErrEmptyOrWhite
+	}
+	return s, nil
+}
+
+// MustNotEmptyString returns NotEmptyString initialized with given value or panics.
+func MustNotEmptyString(s string) NotEmptyString { return must(NotEmptyString{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*NotEmptyString) Type() string { return "NotEmptyString" }
+
+// String implements [flag.Value] interface.
+func (v *NotEmptyString) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &s
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *NotEmptyString) Value(err *error) string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // OneOfString can be set to any of predefined (using NewOneOfString or
 // MustOneOfString) values.
 type OneOfString struct {
-	value *string
+	scalar[string, oneOfParser]
+}
+
+type oneOfParser struct {
 	oneOf []string
 }
 
-func (v *OneOfString) set(s string) error {
-	if slices.Contains(v.oneOf, s) {
-		v.value = &s
-		return nil
+func (p oneOfParser) parse(s string) (string, error) {
+	if slices.Contains(p.oneOf, s) {
+		return s, nil
+	}
+	return "", fmt.Errorf("%w %q", ErrNotOneOf, p.oneOf)
+}
+
+// NewOneOfString returns OneOfString without value set.
+func NewOneOfString(oneOf []string) OneOfString {
+	return OneOfString{scalar[string, oneOfParser]{parser: oneOfParser{oneOf: oneOf}}}
+}
+
+// MustOneOfString returns OneOfString initialized with given value or panics.
+func MustOneOfString(s string, oneOf []string) OneOfString {
+	return must(NewOneOfString(oneOf), s)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*OneOfString) Type() string { return "OneOfString" }
+
+// String implements [flag.Value] interface.
+func (v *OneOfString) String() string {
+	if v == nil {
+		return ""
 	}
-	return fmt.Errorf("%w %q", errNotOneOf, v.oneOf)
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *OneOfString) Value(err *error) string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Endpoint can be set only to valid url with hostname. Also it'll trim
 // all / symbols at end, to make it easier to append paths to endpoint.
 type Endpoint struct {
-	value *string
+	scalar[string, endpointParser]
 }
 
-func (v *Endpoint) set(s string) error {
+type endpointParser struct{}
+
+func (endpointParser) parse(s string) (string, error) {
 	s = strings.TrimRight(s, "/")
 	p, err := url.Parse(s)
 	if err != nil {
-		return err
+		return "", err
 	} else if p.Host == "" {
-		return errNoHost
+		return "", ErrNoHost
+	}
+	return s, nil
+}
+
+// MustEndpoint returns Endpoint initialized with given value or panics.
+func MustEndpoint(s string) Endpoint { return must(Endpoint{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Endpoint) Type() string { return "Endpoint" }
+
+// String implements [flag.Value] interface.
+func (v *Endpoint) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &s
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Endpoint) Value(err *error) string { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Int can be set to integer value.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type Int struct {
-	value *int
+	scalar[int, intParser]
 }
 
-func (v *Int) set(s string) error {
+type intParser struct{}
+
+func (intParser) parse(s string) (int, error) {
 	i64, err := strconv.ParseInt(s, 0, strconv.IntSize)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	i := int(i64)
 	if int64(i) != i64 {
-		return fmt.Errorf("%w int: %s", errOverflows, s)
+		return 0, fmt.Errorf("%w int: %s", errOverflows, s)
+	}
+	return i, nil
+}
+
+// MustInt returns Int initialized with given value or panics.
+func MustInt(s string) Int { return must(Int{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Int) Type() string { return "Int" }
+
+// String implements [flag.Value] interface.
+func (v *Int) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &i
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Int) Value(err *error) int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Int64 can be set to 64-bit integer value.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type Int64 struct {
-	value *int64
+	scalar[int64, int64Parser]
 }
 
-func (v *Int64) set(s string) error {
-	i, err := strconv.ParseInt(s, 0, parseBits)
-	if err != nil {
-		return err
+type int64Parser struct{}
+
+func (int64Parser) parse(s string) (int64, error) {
+	return strconv.ParseInt(s, 0, parseBits)
+}
+
+// MustInt64 returns Int64 initialized with given value or panics.
+func MustInt64(s string) Int64 { return must(Int64{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Int64) Type() string { return "Int64" }
+
+// String implements [flag.Value] interface.
+func (v *Int64) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &i
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Int64) Value(err *error) int64 { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Uint can be set to unsigned integer value.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type Uint struct {
-	value *uint
+	scalar[uint, uintParser]
 }
 
-func (v *Uint) set(s string) error {
+type uintParser struct{}
+
+func (uintParser) parse(s string) (uint, error) {
 	i64, err := strconv.ParseUint(s, 0, strconv.IntSize)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	i := uint(i64)
 	if uint64(i) != i64 {
-		return fmt.Errorf("%w unsigned int: %s", errOverflows, s)
+		return 0, fmt.Errorf("%w unsigned int: %s", errOverflows, s)
+	}
+	return i, nil
+}
+
+// MustUint returns Uint initialized with given value or panics.
+func MustUint(s string) Uint { return must(Uint{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Uint) Type() string { return "Uint" }
+
+// String implements [flag.Value] interface.
+func (v *Uint) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &i
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Uint) Value(err *error) uint { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Uint64 can be set to unsigned 64-bit integer value.
 // It's allowed to use 0b, 0o and 0x prefixes, and also underscores.
 type Uint64 struct {
-	value *uint64
+	scalar[uint64, uint64Parser]
 }
 
-func (v *Uint64) set(s string) error {
-	i, err := strconv.ParseUint(s, 0, parseBits)
-	if err != nil {
-		return err
+type uint64Parser struct{}
+
+func (uint64Parser) parse(s string) (uint64, error) {
+	return strconv.ParseUint(s, 0, parseBits)
+}
+
+// MustUint64 returns Uint64 initialized with given value or panics.
+func MustUint64(s string) Uint64 { return must(Uint64{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Uint64) Type() string { return "Uint64" }
+
+// String implements [flag.Value] interface.
+func (v *Uint64) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &i
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Uint64) Value(err *error) uint64 { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Float64 can be set to 64-bit floating-point number.
 type Float64 struct {
-	value *float64
+	scalar[float64, float64Parser]
 }
 
-func (v *Float64) set(s string) error {
-	i, err := strconv.ParseFloat(s, parseBits)
-	if err != nil {
-		return err
+type float64Parser struct{}
+
+func (float64Parser) parse(s string) (float64, error) {
+	return strconv.ParseFloat(s, parseBits)
+}
+
+// MustFloat64 returns Float64 initialized with given value or panics.
+func MustFloat64(s string) Float64 { return must(Float64{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Float64) Type() string { return "Float64" }
+
+// String implements [flag.Value] interface.
+func (v *Float64) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &i
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Float64) Value(err *error) float64 { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // IntBetween can be set to integer value between given (using
 // NewIntBetween or MustIntBetween) min/max values (inclusive).
 type IntBetween struct {
-	value    *int
+	scalar[int, intBetweenParser]
+}
+
+type intBetweenParser struct {
 	min, max int
 }
 
-func (v *IntBetween) set(s string) error {
+func (p intBetweenParser) parse(s string) (int, error) {
 	i, err := strconv.Atoi(s)
 	if err != nil {
-		return err
-	} else if v.min > i || i > v.max {
-		return fmt.Errorf("%w %d and %d", errNotBetween, v.min, v.max)
+		return 0, err
+	} else if p.min > i || i > p.max {
+		return 0, fmt.Errorf("%w %d and %d", ErrNotBetween, p.min, p.max)
+	}
+	return i, nil
+}
+
+// NewIntBetween returns IntBetween without value set.
+func NewIntBetween(minVal, maxVal int) IntBetween {
+	return IntBetween{scalar[int, intBetweenParser]{parser: intBetweenParser{min: minVal, max: maxVal}}}
+}
+
+// MustIntBetween returns IntBetween initialized with given value or panics.
+func MustIntBetween(s string, minVal, maxVal int) IntBetween {
+	return must(NewIntBetween(minVal, maxVal), s)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*IntBetween) Type() string { return "IntBetween" }
+
+// String implements [flag.Value] interface.
+func (v *IntBetween) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &i
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *IntBetween) Value(err *error) int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // Port can be set to integer value between 1 and 65535.
 type Port struct {
-	value *int
+	scalar[int, portParser]
 }
 
-func (v *Port) set(s string) error {
+type portParser struct{}
+
+func (portParser) parse(s string) (int, error) {
 	i, err := strconv.Atoi(s)
 	if err != nil {
-		return err
+		return 0, err
 	} else if 0 >= i || i > math.MaxUint16 {
-		return fmt.Errorf("%w 1 and %d", errNotBetween, math.MaxUint16)
+		return 0, fmt.Errorf("%w 1 and %d", ErrNotBetween, math.MaxUint16)
+	}
+	return i, nil
+}
+
+// MustPort returns Port initialized with given value or panics.
+func MustPort(s string) Port { return must(Port{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*Port) Type() string { return "Port" }
+
+// String implements [flag.Value] interface.
+func (v *Port) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &i
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Port) Value(err *error) int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // ListenPort can be set to integer value between 0 and 65535.
 type ListenPort struct {
-	value *int
+	scalar[int, listenPortParser]
 }
 
-func (v *ListenPort) set(s string) error {
+type listenPortParser struct{}
+
+func (listenPortParser) parse(s string) (int, error) {
 	i, err := strconv.Atoi(s)
 	if err != nil {
-		return err
+		return 0, err
 	} else if 0 > i || i > math.MaxUint16 {
-		return fmt.Errorf("%w 0 and %d", errNotBetween, math.MaxUint16)
+		return 0, fmt.Errorf("%w 0 and %d", ErrNotBetween, math.MaxUint16)
 	}
-	v.value = &i
-	return nil
+	return i, nil
 }
 
+// MustListenPort returns ListenPort initialized with given value or panics.
+func MustListenPort(s string) ListenPort { return must(ListenPort{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*ListenPort) Type() string { return "ListenPort" }
+
+// String implements [flag.Value] interface.
+func (v *ListenPort) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.scalar.String()
+}
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *ListenPort) Value(err *error) int { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // IPNet can be set to CIDR address.
 type IPNet struct {
-	value **net.IPNet
+	scalar[*net.IPNet, ipNetParser]
 }
 
-func (v *IPNet) set(s string) error {
+type ipNetParser struct{}
+
+func (ipNetParser) parse(s string) (*net.IPNet, error) {
 	_, ipNet, err := net.ParseCIDR(s)
-	if err != nil {
-		return err
+	return ipNet, err
+}
+
+// MustIPNet returns IPNet initialized with given value or panics.
+func MustIPNet(s string) IPNet { return must(IPNet{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*IPNet) Type() string { return "IPNet" }
+
+// String implements [flag.Value] interface.
+func (v *IPNet) String() string {
+	if v == nil {
+		return ""
 	}
-	v.value = &ipNet
-	return nil
+	return v.scalar.String()
 }
 
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *IPNet) Value(err *error) *net.IPNet { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
 // HostPort can be set to CIDR address.
 type HostPort struct {
-	value *string
-	host  string
-	port  int
+	scalar[string, hostPortParser]
+}
+
+type hostPortParser struct{}
+
+func (hostPortParser) parse(s string) (string, error) {
+	_, err := splitHostPort(s)
+	return s, err
 }
 
-func (v *HostPort) set(s string) error {
+func splitHostPort(s string) (tuple HostPortTuple, err error) {
 	host, port, err := net.SplitHostPort(s)
 	if err != nil {
-		return err
+		return tuple, err
 	}
 	if host == "" {
-		return errNoHost
+		return tuple, ErrNoHost
 	}
-	v.port, err = strconv.Atoi(port)
+	tuple.Port, err = strconv.Atoi(port)
 	if err != nil {
-		return fmt.Errorf("port: %w", err)
+		return tuple, fmt.Errorf("port: %w", err)
+	}
+	tuple.Host = host
+	return tuple, nil
+}
+
+// MustHostPort returns HostPort initialized with given value or panics.
+func MustHostPort(s string) HostPort { return must(HostPort{}, s) }
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+func (*HostPort) Type() string { return "HostPort" }
+
+// String implements [flag.Value] interface.
+func (v *HostPort) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.scalar.String()
+}
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *HostPort) Value(err *error) (host string, port int) { //nolint:gocritic // ptrToRefParam.
+	s := v.get(err, v)
+	if s == "" {
+		return host, port
 	}
-	v.host = host
-	v.value = &s
-	return nil
+	tuple, _ := splitHostPort(s) // Already validated by Set.
+	return tuple.Host, tuple.Port
 }
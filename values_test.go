@@ -1,6 +1,13 @@
 package appcfg_test
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/powerman/check"
@@ -175,3 +182,240 @@ func TestHostPort(tt *testing.T) {
 	t.PanicMatch(func() { v = appcfg.MustHostPort("localhost:http") }, "port: .* parsing")
 	t.PanicMatch(func() { v = appcfg.MustHostPort(":80") }, "no host")
 }
+
+func TestSecret(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var v appcfg.NotEmptySecret
+	t.Equal(v.Type(), "NotEmptySecret")
+	t.Equal(v.String(), "")
+	var err error
+	t.Equal(v.Value(&err), "")
+	t.Match(err, "required")
+
+	t.Match(v.Set(" "), "empty")
+	t.Nil(v.Set("s3cret"))
+	t.Equal(v.Get(), "s3cret")
+	err = nil
+	t.Equal(v.Value(&err), "s3cret")
+	t.Nil(err)
+	t.Equal(v.String(), "******")
+	t.Equal(fmt.Sprint(v), "******")
+	t.Equal(fmt.Sprintf("%v %s %q", &v, v, v), "****** ****** ******")
+	t.Equal(fmt.Sprintf("%#v", v), "appcfg.NotEmptySecret{******}")
+
+	cfg := struct {
+		Password appcfg.SecretString `env:"PASSWORD"`
+	}{Password: appcfg.MustSecretString("s3cret")}
+	t.NotContains(fmt.Sprintf("%v %+v %#v", cfg, cfg, cfg), "s3cret")
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("cfg", "password", cfg.Password)
+	t.Contains(buf.String(), "password=******")
+	t.Equal(appcfg.SourceOf(&cfg.Password).Raw, "******")
+
+	fromDotenv, err := appcfg.NewFromDotenv(strings.NewReader("PASSWORD=' '"), "")
+	t.Nil(err)
+	var bad struct {
+		Password appcfg.NotEmptySecret `env:"PASSWORD"`
+	}
+	err = appcfg.ProvideStruct(&bad, fromDotenv)
+	t.Match(err, `\$PASSWORD=\*+: empty`)
+	t.NotContains(err.Error(), `" "`)
+	t.Equal(appcfg.FieldErrors(err)[0].Raw, "******")
+	t.True(errors.Is(err, appcfg.ErrEmptyOrWhite))
+}
+
+func TestNilString(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	values := []appcfg.Value{
+		(*appcfg.Duration)(nil),
+		(*appcfg.Bool)(nil),
+		(*appcfg.String)(nil),
+		(*appcfg.NotEmptyString)(nil),
+		(*appcfg.OneOfString)(nil),
+		(*appcfg.Endpoint)(nil),
+		(*appcfg.Int)(nil),
+		(*appcfg.Int64)(nil),
+		(*appcfg.Uint)(nil),
+		(*appcfg.Uint64)(nil),
+		(*appcfg.Float64)(nil),
+		(*appcfg.IntBetween)(nil),
+		(*appcfg.Port)(nil),
+		(*appcfg.ListenPort)(nil),
+		(*appcfg.IPNet)(nil),
+		(*appcfg.HostPort)(nil),
+		(*appcfg.DurationSlice)(nil),
+		(*appcfg.BoolSlice)(nil),
+		(*appcfg.StringArray)(nil),
+		(*appcfg.StringSlice)(nil),
+		(*appcfg.NotEmptyStringArray)(nil),
+		(*appcfg.NotEmptyStringSlice)(nil),
+		(*appcfg.OneOfStringSlice)(nil),
+		(*appcfg.EndpointSlice)(nil),
+		(*appcfg.IntSlice)(nil),
+		(*appcfg.Int64Slice)(nil),
+		(*appcfg.UintSlice)(nil),
+		(*appcfg.Uint64Slice)(nil),
+		(*appcfg.Float64Slice)(nil),
+		(*appcfg.IntBetweenSlice)(nil),
+		(*appcfg.PortSlice)(nil),
+		(*appcfg.ListenPortSlice)(nil),
+		(*appcfg.IPNetSlice)(nil),
+		(*appcfg.HostPortSlice)(nil),
+		(*appcfg.SecretString)(nil),
+		(*appcfg.NotEmptySecret)(nil),
+		(*appcfg.Var[int])(nil),
+		(*appcfg.VarSlice[int])(nil),
+	}
+	for _, v := range values {
+		t.Equal(v.String(), "", fmt.Sprintf("%T", v))
+	}
+	t.Equal(fmt.Sprint((*appcfg.SecretString)(nil)), "<nil>")
+	t.Equal(fmt.Sprint((*appcfg.NotEmptySecret)(nil)), "<nil>")
+}
+
+func isComparable[T comparable]() {}
+
+func TestComparable(*testing.T) {
+	isComparable[appcfg.Duration]()
+	isComparable[appcfg.Bool]()
+	isComparable[appcfg.String]()
+	isComparable[appcfg.NotEmptyString]()
+	isComparable[appcfg.Endpoint]()
+	isComparable[appcfg.Int]()
+	isComparable[appcfg.Int64]()
+	isComparable[appcfg.Uint]()
+	isComparable[appcfg.Uint64]()
+	isComparable[appcfg.Float64]()
+	isComparable[appcfg.IntBetween]()
+	isComparable[appcfg.Port]()
+	isComparable[appcfg.ListenPort]()
+	isComparable[appcfg.IPNet]()
+	isComparable[appcfg.HostPort]()
+	isComparable[appcfg.SecretString]()
+	isComparable[appcfg.NotEmptySecret]()
+}
+
+func TestOf(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	v := appcfg.Of(url.Parse)
+	t.Equal(v.Type(), "*url.URL")
+
+	t.Equal(v.String(), "")
+	t.Nil(v.Get())
+	var err error
+	t.Nil(v.Value(&err))
+	t.Match(err, "required")
+
+	t.Nil(v.Set("http://localhost/"))
+	t.Equal(v.String(), "http://localhost/")
+	err = nil
+	t.Equal(v.Value(&err).Host, "localhost")
+	t.Nil(err)
+
+	t.Match(v.Set(":"), "missing protocol scheme")
+	t.Nil(v.Get())
+
+	v = appcfg.MustOf("/path", url.Parse)
+	t.Equal(v.String(), "/path")
+	t.PanicMatch(func() { v = appcfg.MustOf(":", url.Parse) }, "missing protocol scheme")
+
+	var cfg struct {
+		URL appcfg.Var[*url.URL] `key:"url"`
+	}
+	t.Match(appcfg.ProvideStruct(&cfg, fromMap{"url": "http://example.com"}), `^URL .*no parse function`)
+	t.Nil(cfg.URL.Get())
+	cfg.URL = appcfg.Of(url.Parse)
+	t.Nil(appcfg.ProvideStruct(&cfg, fromMap{"url": "http://example.com"}))
+	t.Equal(cfg.URL.String(), "http://example.com")
+}
+
+func TestSliceOf(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	v := appcfg.SliceOf(netip.ParseAddr)
+	t.Equal(v.Type(), "[]netip.Addr")
+
+	t.Equal(v.String(), "")
+	t.Nil(v.Get())
+	var err error
+	t.Nil(v.Value(&err))
+	t.Match(err, "required")
+
+	t.Nil(v.Set(""))
+	t.Equal(v.String(), "[]")
+	err = nil
+	t.NotNil(v.Value(&err))
+	t.Len(v.Value(&err), 0)
+	t.Nil(err)
+
+	t.Nil(v.Set("127.0.0.1,::1"))
+	t.Nil(v.Set("10.0.0.1"))
+	t.Equal(v.String(), "[127.0.0.1 ::1 10.0.0.1]")
+
+	t.Match(v.Set("10.0.0.1,x"), "ParseAddr")
+	t.Nil(v.Get())
+
+	v = appcfg.MustSliceOf(netip.ParseAddr, "127.0.0.1", "::1")
+	t.Equal(v.String(), "[127.0.0.1 ::1]")
+	t.Nil(v.Set("10.0.0.1"))
+	t.Equal(v.String(), "[10.0.0.1]")
+
+	t.PanicMatch(func() { v = appcfg.MustSliceOf(netip.ParseAddr) }, "require at least 1 arg")
+
+	var zero appcfg.VarSlice[netip.Addr]
+	t.Match(zero.Set("127.0.0.1"), "no parse function")
+	t.Nil(zero.Get())
+}
+
+func TestSlice(tt *testing.T) {
+	t := check.T(tt)
+	t.Parallel()
+
+	var ints appcfg.IntSlice
+	t.Nil(ints.Set(""))
+	t.DeepEqual(ints.Get(), []int{})
+	t.Nil(ints.Set("1,2"))
+	t.DeepEqual(ints.Get(), []int{1, 2})
+	ints.Get().([]int)[0] = 0
+	var err error
+	ints.Value(&err)[1] = 0
+	t.DeepEqual(ints.Get(), []int{1, 2})
+	t.Nil(ints.Set("3"))
+	t.Match(ints.Set(""), "invalid syntax")
+	t.Nil(ints.Get())
+
+	var ss appcfg.StringSlice
+	t.Nil(ss.Set(""))
+	t.Nil(ss.Set("a,b"))
+	t.DeepEqual(ss.Get(), []string{"", "a", "b"})
+
+	var sa appcfg.StringArray
+	t.Nil(sa.Set(""))
+	t.Nil(sa.Set("a,b"))
+	t.DeepEqual(sa.Get(), []string{"", "a,b"})
+
+	ne := appcfg.MustNotEmptyStringArray("a,b", "c")
+	t.DeepEqual(ne.Get(), []string{"a,b", "c"})
+	t.Match(ne.Set(" "), "empty")
+
+	ep := appcfg.MustEndpointSlice("http://a,b/")
+	t.DeepEqual(ep.Get(), []string{"http://a,b"})
+
+	hp := appcfg.MustHostPortSlice("a:1,b:2")
+	t.DeepEqual(hp.Value(&err), []appcfg.HostPortTuple{{Host: "a", Port: 1}, {Host: "b", Port: 2}})
+	t.Nil(err)
+
+	between := appcfg.MustIntBetweenSlice(1, 3, "1,2", "3")
+	t.Equal(between.String(), "[1 2 3]")
+	t.Match(between.Set("4"), "not between 1 and 3")
+	oneOf := appcfg.NewOneOfStringSlice([]string{"a", "b"})
+	t.Nil(oneOf.Set("b,a"))
+	t.Match(oneOf.Set("c"), `not one of \["a" "b"\]`)
+}
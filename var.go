@@ -0,0 +1,90 @@
+package appcfg
+
+import (
+	"errors"
+	"reflect"
+)
+
+var errNoParse = errors.New("no parse function (use Of or SliceOf)")
+
+// Var can be set to any string valid for parse function given to Of or
+// MustOf. It's a way to implement Value for any type T without defining
+// a new type.
+//
+// Zero Var (created without Of or MustOf) can't be set: Set returns
+// error.
+type Var[T any] struct {
+	scalar[T, parseFunc[T]]
+}
+
+type parseFunc[T any] func(string) (T, error)
+
+func (f parseFunc[T]) parse(s string) (val T, err error) {
+	if f == nil {
+		return val, errNoParse
+	}
+	return f(s)
+}
+
+// Of returns Var without value set which will use parse to set value.
+func Of[T any](parse func(string) (T, error)) Var[T] {
+	return Var[T]{scalar[T, parseFunc[T]]{parser: parse}}
+}
+
+// MustOf returns Var initialized with given value or panics.
+func MustOf[T any](s string, parse func(string) (T, error)) Var[T] {
+	return must(Of(parse), s)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+// It returns name of type T.
+func (*Var[T]) Type() string { return reflect.TypeFor[T]().String() }
+
+// String implements [flag.Value] interface.
+func (v *Var[T]) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.scalar.String()
+}
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *Var[T]) Value(err *error) T { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.
+
+// VarSlice can be set to comma-separated strings valid for parse
+// function given to SliceOf or MustSliceOf. It's a way to implement
+// Value for slice of any type T without defining a new type.
+//
+// Zero VarSlice (created without SliceOf or MustSliceOf) can't be set:
+// Set returns error.
+type VarSlice[T any] struct {
+	slice[T, parseFunc[T]]
+}
+
+// SliceOf returns VarSlice without value set which will use parse to set
+// values.
+func SliceOf[T any](parse func(string) (T, error)) VarSlice[T] {
+	return VarSlice[T]{slice[T, parseFunc[T]]{parser: parse}}
+}
+
+// MustSliceOf returns VarSlice initialized with given values or panics.
+func MustSliceOf[T any](parse func(string) (T, error), ss ...string) VarSlice[T] {
+	return must(SliceOf(parse), ss...)
+}
+
+// Type implements [github.com/spf13/pflag.Value] interface.
+// It returns name of type []T.
+func (*VarSlice[T]) Type() string { return reflect.TypeFor[[]T]().String() }
+
+// String implements [flag.Value] interface.
+func (v *VarSlice[T]) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.slice.String()
+}
+
+// Value is like Get except it returns zero value and set *err to
+// RequiredError if unset.
+func (v *VarSlice[T]) Value(err *error) []T { return v.get(err, v) } //nolint:gocritic // ptrToRefParam.